@@ -0,0 +1,22 @@
+package env
+
+// Chain returns a lookup function that consults each of the given sources in
+// order, and returns the value from the first source that contains the key.
+// If no source contains the key, the second return value will be false.
+//
+// This may be combined with [WithLookup] to make the precedence of layered
+// configuration explicit, such as preferring the real environment over a
+// .env file, and a .env file over defaults:
+//
+//	lookup := env.Chain(os.LookupEnv, fileEnv.LookupEnv, defaults.LookupEnv)
+//	err := env.Unmarshal(&cfg, env.WithLookup(lookup))
+func Chain(sources ...func(key string) (string, bool)) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		for _, source := range sources {
+			if value, ok := source(key); ok {
+				return value, true
+			}
+		}
+		return "", false
+	}
+}
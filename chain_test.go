@@ -0,0 +1,81 @@
+package env_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"rodusek.dev/pkg/env"
+)
+
+func TestChain(t *testing.T) {
+	first := env.Environment{"NAME": "first"}
+	second := env.Environment{"NAME": "second", "PORT": "8080"}
+	testCases := []struct {
+		name    string
+		sources []func(string) (string, bool)
+		key     string
+		want    string
+		wantOK  bool
+	}{
+		{
+			name:    "Earlier source wins",
+			sources: []func(string) (string, bool){first.LookupEnv, second.LookupEnv},
+			key:     "NAME",
+			want:    "first",
+			wantOK:  true,
+		}, {
+			name:    "Miss falls through to later source",
+			sources: []func(string) (string, bool){first.LookupEnv, second.LookupEnv},
+			key:     "PORT",
+			want:    "8080",
+			wantOK:  true,
+		}, {
+			name:    "Miss in all sources",
+			sources: []func(string) (string, bool){first.LookupEnv, second.LookupEnv},
+			key:     "MISSING",
+			want:    "",
+			wantOK:  false,
+		}, {
+			name:    "No sources",
+			sources: nil,
+			key:     "NAME",
+			want:    "",
+			wantOK:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lookup := env.Chain(tc.sources...)
+
+			got, ok := lookup(tc.key)
+
+			if got, want := ok, tc.wantOK; got != want {
+				t.Errorf("Chain(%s): got ok '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Chain(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestChain_WithLookup(t *testing.T) {
+	type ChainEnv struct {
+		Name string `env:"NAME"`
+		Port int    `env:"PORT"`
+	}
+	overrides := env.Environment{"NAME": "override"}
+	defaults := env.Environment{"NAME": "default", "PORT": "80"}
+
+	var out ChainEnv
+	err := env.Unmarshal(&out, env.WithLookup(env.Chain(overrides.LookupEnv, defaults.LookupEnv)))
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := ChainEnv{Name: "override", Port: 80}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package env
+
+import (
+	"os"
+	"reflect"
+)
+
+// Decoder decodes environment variables into structs, like [Unmarshal], with a
+// configurable set of defaults. This avoids repeating the same options on every
+// call for programs that standardize on, for example, a non-comma separator,
+// or that repeatedly decode from the same lookup source. This mirrors how a
+// [encoding/json.Decoder] holds its settings across calls.
+//
+// The zero value is ready to use and behaves exactly like [Unmarshal]. A
+// Decoder holding a set of options may be created with [NewDecoder]. The
+// defaults of a Decoder are applied before any options passed to
+// [Decoder.Unmarshal], so that per-call options such as [Separator] and
+// per-field tag options such as `sep` still take precedence.
+type Decoder struct {
+	// Separator is the default separator for splitting slice and map values. If
+	// empty, the default of ',' is used.
+	Separator string
+
+	// KeyValueSeparator is the default separator between the key and value of
+	// each entry of map values. If empty, the default of '=' is used.
+	KeyValueSeparator string
+
+	// opts are the options supplied to NewDecoder.
+	opts []UnmarshalOption
+}
+
+// NewDecoder creates a new [Decoder] that applies the given options, such as
+// [WithLookup] or [WithFeatures], to every value it decodes.
+func NewDecoder(opts ...UnmarshalOption) *Decoder {
+	return &Decoder{
+		opts: opts,
+	}
+}
+
+// Decode reads values from the lookup source of the decoder, which is the
+// current environment by default, and parses them into the provided output
+// struct, as by [Unmarshal].
+func (d *Decoder) Decode(out any) error {
+	return d.Unmarshal(out)
+}
+
+// Unmarshal reads values from the current environment and parses them into the
+// provided output struct, as by [Unmarshal], using the defaults of the decoder.
+func (d *Decoder) Unmarshal(out any, opts ...UnmarshalOption) error {
+	// Nothing in, no error taking it out. Seems reasonable?
+	if out == nil {
+		return nil
+	}
+
+	opts = d.options(opts)
+	rv := reflect.ValueOf(out)
+	tag := newTagOptions(opts...)
+	return tag.lookupFailed(decode(tag.lookupOr(os.LookupEnv), rv, opts...))
+}
+
+// options returns the default options of the decoder, followed by the options
+// supplied to [NewDecoder], followed by the given options.
+func (d *Decoder) options(opts []UnmarshalOption) []UnmarshalOption {
+	var result []UnmarshalOption
+	if d.Separator != "" {
+		result = append(result, Separator(d.Separator))
+	}
+	if d.KeyValueSeparator != "" {
+		result = append(result, KeyValueSeparator(d.KeyValueSeparator))
+	}
+	result = append(result, d.opts...)
+	return append(result, opts...)
+}
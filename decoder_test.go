@@ -0,0 +1,130 @@
+package env_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"rodusek.dev/pkg/env"
+)
+
+func TestDecoderUnmarshal_Separators(t *testing.T) {
+	type DecoderEnv struct {
+		Names  []string          `env:"DECODER_NAMES"`
+		Ports  []int             `env:"DECODER_PORTS,sep=|"`
+		Labels map[string]string `env:"DECODER_LABELS"`
+	}
+	setenv(t, `
+		DECODER_NAMES=a;b
+		DECODER_PORTS=80|443
+		DECODER_LABELS=team:payments;env:prod
+	`)
+
+	testCases := []struct {
+		name    string
+		decoder env.Decoder
+		opts    []env.UnmarshalOption
+		want    DecoderEnv
+	}{
+		{
+			name:    "Decoder defaults",
+			decoder: env.Decoder{Separator: ";", KeyValueSeparator: ":"},
+			want: DecoderEnv{
+				Names:  []string{"a", "b"},
+				Ports:  []int{80, 443},
+				Labels: map[string]string{"team": "payments", "env": "prod"},
+			},
+		}, {
+			name:    "Per-call option overrides decoder",
+			decoder: env.Decoder{Separator: ";", KeyValueSeparator: ":"},
+			opts:    []env.UnmarshalOption{env.Separator(",")},
+			want: DecoderEnv{
+				Names:  []string{"a;b"},
+				Ports:  []int{80, 443},
+				Labels: map[string]string{"team": "payments;env:prod"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got DecoderEnv
+			if err := tc.decoder.Unmarshal(&got, tc.opts...); err != nil {
+				t.Fatalf("Decoder.Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Decoder.Unmarshal(%s): mismatch (-want +got):\n%s", tc.name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestDecoderUnmarshal_ZeroValue_UsesDefaults(t *testing.T) {
+	setenv(t, "DECODER_NAMES=a,b")
+
+	var got struct {
+		Names []string `env:"DECODER_NAMES"`
+	}
+	var decoder env.Decoder
+	if err := decoder.Unmarshal(&got); err != nil {
+		t.Fatalf("Decoder.Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := got.Names, []string{"a", "b"}; !cmp.Equal(got, want) {
+		t.Errorf("Decoder.Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestNewDecoder_Decode(t *testing.T) {
+	type DecoderEnv struct {
+		Name  string   `env:"NAME,required"`
+		Ports []int    `env:"PORTS"`
+		Debug bool     `env:"DEBUG,feature=debug"`
+		Tags  []string `env:"TAGS,sep=|"`
+	}
+	source := env.Environment{
+		"NAME":  "example",
+		"PORTS": "80;443",
+		"DEBUG": "true",
+		"TAGS":  "a|b",
+	}
+	sut := env.NewDecoder(
+		env.WithLookup(source.LookupEnv),
+		env.Separator(";"),
+		env.WithFeatures("debug"),
+	)
+
+	for i := 0; i < 2; i++ {
+		var got DecoderEnv
+		if err := sut.Decode(&got); err != nil {
+			t.Fatalf("Decoder.Decode(): unexpected error: %v", err)
+		}
+
+		want := DecoderEnv{Name: "example", Ports: []int{80, 443}, Debug: true, Tags: []string{"a", "b"}}
+		if !cmp.Equal(got, want) {
+			t.Errorf("Decoder.Decode(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+		}
+	}
+}
+
+func TestNewDecoder_UnmarshalOptions_OverrideDecoderOptions(t *testing.T) {
+	source := env.Environment{"PORTS": "80,443"}
+	sut := env.NewDecoder(env.WithLookup(source.LookupEnv), env.Separator(";"))
+
+	var got struct {
+		Ports []int `env:"PORTS"`
+	}
+	if err := sut.Unmarshal(&got, env.Separator(",")); err != nil {
+		t.Fatalf("Decoder.Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := got.Ports, []int{80, 443}; !cmp.Equal(got, want) {
+		t.Errorf("Decoder.Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestDecoderDecode_NilOutput_ReturnsNil(t *testing.T) {
+	if err := env.NewDecoder().Decode(nil); err != nil {
+		t.Errorf("Decoder.Decode(): got err '%v', want nil", err)
+	}
+}
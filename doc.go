@@ -4,5 +4,245 @@ Package env is a small package for managing environment variables.
 Unlike the standard [os.Getenv] approach, this package models itself after
 the [encoding/json] package, providing a way to marshal and unmarshal
 environment variables into a structured format using env tags.
+
+# Keys
+
+Fields are interpreted based on their `env` tag, which defines the variable
+key to read from, followed by any comma-separated options. If this tag is not
+set, the field name is converted to screaming snake case with [ScreamingSnake]
+and used instead (e.g. the field `ProjectName` would use the environment
+variable `PROJECT_NAME`). A different naming strategy may be supplied with
+[NameFunc]. Unexported fields are ignored, as are fields tagged `env:"-"`,
+which are never read or modified. A field may still be read from the literal
+key "-" with the tag `env:"-,"`.
+
+# Supported Types
+
+Values may be parsed into the following types:
+
+  - string types
+  - integral types (byte, int, int8, int16, int32, int64, uint, uint8,
+    uint16, uint32, uint64)
+  - floating point types (float32, float64)
+  - complex types (complex64, complex128)
+  - boolean types
+  - [time.Duration] (using [time.ParseDuration] format)
+  - [time.Time] (using [time.Parse], using all common time format layouts)
+  - [os.FileMode] (as octal permission bits, such as "0644" or "755")
+  - [math/big.Int] and [math/big.Float] (detecting the 0x/0o/0b base prefixes)
+  - [regexp.Regexp] (using [regexp.Compile]), typically as a pointer
+  - [Unmarshaler]
+  - [encoding.TextUnmarshaler]
+  - [database/sql.Scanner], such as [database/sql.NullString], which are
+    scanned from the raw string (and so become valid only when set)
+  - [fmt.Scanner] (as a last resort for otherwise unsupported types)
+  - slices of any of the above supported types (an empty value decodes
+    into an empty, non-nil slice, whereas an unset value leaves it nil)
+  - arrays of any of the above supported types, which must have exactly as
+    many elements as the length of the array
+  - maps with keys and values of any of the above supported types
+  - slices of structs, read from keys with a numeric index suffix
+
+Named types, such as `type Region string`, are decoded as their underlying
+type unless they implement one of the interfaces above. Note that this
+includes byte slices: a `[]byte`, or a named type such as `type Blob []byte`,
+is decoded as a slice of numbers like any other slice, so `BLOB=1,2,3` is
+decoded as []byte{1, 2, 3} rather than as the raw bytes of the value.
+
+Values are decoded through [encoding.TextUnmarshaler] only after the types
+with built-in handling above, such as [time.Time] and [math/big.Int], and after
+[Unmarshaler]. The `string` option instead forces values through the
+UnmarshalText method of the field's type (or of the elements of a slice),
+ahead of any other handling. For example, a [time.Time] field tagged
+`env:"START,string"` only accepts the RFC 3339 format of
+[time.Time.UnmarshalText], rather than all of the layouts that are otherwise
+accepted. Fields of string kinds that do not implement
+[encoding.TextUnmarshaler] are unaffected by this option.
+
+Pointer fields are only allocated if their key is set, so that a nil pointer
+distinguishes a variable that was not set from one that was set to its zero
+value. This holds for pointers to any supported type, including slices, maps,
+and nested structs; the only exception is bool fields with the `presence`
+option, which are always decoded. Pointers that are already non-nil are
+decoded through, preserving the value they point to.
+
+# Structs
+
+Untagged embedded structs are decoded as if their fields were declared in the
+embedding struct. Embedded pointers to structs are treated as optional
+sections: they are only allocated if at least one of their keys is set, and
+are left nil otherwise, in which case none of their fields are required.
+This also applies to embedded structs of unexported types, whose exported
+fields are promoted as they are by encoding/json; however, a nil embedded
+pointer to an unexported type cannot be allocated, so setting any of its keys
+is an error.
+
+Slices of structs are decoded from keys consisting of the field's key, a
+numeric index, and the key of each struct field, separated by underscores.
+For example, a field `Upstreams []Upstream` tagged `env:"UPSTREAM"` reads
+the keys `UPSTREAM_0_HOST`, `UPSTREAM_1_HOST`, and so on. The slice grows to
+cover every contiguous index starting from 0 with at least one key set, and
+decoding stops at the first gap in the indices; any later indices are
+ignored.
+
+Nested struct fields, or pointers to them, may instead be decoded from the
+value of a single key with the `inline` option, which names the format of the
+value. With `inline=dotenv`, the value is parsed as whitespace-separated
+assignments, such as `DB=host=localhost port=5432`, where values may be
+quoted as in a dotenv file. With `inline=json`, the value is parsed as a JSON
+object, such as `DB={"host":"localhost","port":5432}`. The fields of the
+nested struct are then decoded from the parsed entries, matching their keys
+exactly. Malformed values are reported as a [ParseError].
+
+# Requirements
+
+Fields may be marked as required with the `required` option. By default, a
+required field is satisfied by any value, including an empty one. The
+`nonempty` option, or the [RequireNonEmpty] option for every field,
+additionally treats a required field that is set to an empty value as
+missing. This only affects fields that are both required and set to an empty
+value; fields that are not required still decode empty values as usual.
+
+Fields may also be conditionally required with the `requiredIf` option,
+which names another environment variable. The field is only required when
+that variable is set to a non-empty value that is not a boolean false value
+(such as "0" or "false"). Conditions are always evaluated against the
+environment rather than other decoded fields, so the order fields are
+declared in does not matter.
+
+Fields may be placed into a mutually-exclusive group with the `group` option.
+At most one field in a group may be set, and if any member of the group is
+marked `required` then exactly one must be set. Violations are reported as an
+[ExclusiveGroupError] once all fields of the struct have been decoded.
+
+Fields may be gated behind a named feature with the `feature` option, in
+which case they are only decoded when that feature is enabled with
+[WithFeatures].
+
+# Slices and Maps
+
+Slices and maps are split on a separator, which is ',' by default and may be
+changed with the `sep` option. Each entry of a map is further split into its
+key and value by the `kvsep` option (default is '='), so that a field tagged
+`env:"LABELS,sep=;"` may be set with `LABELS=env=prod;team=payments`. An
+entry missing the key-value separator is reported as a [ParseError].
+
+Values are split naively on the separator by default. The `csv` option, or the
+[CSV] option for every field, instead honors CSV-style quoting so that quoted
+entries may contain the separator, such as `a,"b,c",d`. Alternatively, the
+`escape` option, or the [Escape] option for every field, honors a backslash
+before the separator, so that `a\,b,c` is split into "a,b" and "c"; an
+escaped backslash `\\` is a literal backslash, and any other backslash is kept
+as-is.
+
+Duplicate elements of slice fields may be removed with the `unique` option,
+which keeps the first occurrence of each element in its original order, so
+that `FEATURES=a,b,a` is decoded as ["a", "b"]. Pointer elements are compared
+by the values they point to.
+
+Slice and map fields may have the number of their elements bounded with the
+`minlen` and `maxlen` options, which are both inclusive and are checked after
+duplicates are removed. Lengths outside of these bounds are reported as a
+[ValidationError]; in particular, `required,minlen=1` rejects a value such as
+`PATHS=` that is set, but has no elements.
+
+# Transforming Values
+
+Values are used verbatim by default. The `trim` option trims surrounding
+whitespace from a value before it is parsed, and the `trimprefix` and
+`trimsuffix` options strip a fixed prefix or suffix, in that order. These
+are applied to each element of a slice after it has been split. String fields
+(and slices of strings) may also have their case normalized with the `lower`
+or `upper` options.
+
+Values split across several keys, such as a secret that exceeds a length
+limit, may be joined with the `concat` option, which names another key whose
+value is appended to the field's value before it is parsed. The option may
+be repeated to append several keys in order, and the `join` option inserts a
+string between each of the values. For example, a field tagged
+`env:"CERT,concat=CERT_2,concat=CERT_3"` reads the concatenation of `CERT`,
+`CERT_2`, and `CERT_3`. Keys that are not set are treated as empty, unless the
+field is required, in which case every key must be set.
+
+# Numbers, Durations, and Times
+
+Integer values are parsed with their base inferred from their prefix by
+default, such as "0x" for hexadecimal. The `base` option forces a fixed base
+between 2 and 36 instead, so that `base=16` accepts bare hexadecimal values
+such as "ff00ff". Note that base inference treats a leading zero as octal, so
+"010" is decoded as 8; use `base=10`, or the [DecimalOnly] option, for values
+that may be zero-padded.
+
+Integer fields may instead be parsed as human-readable sizes in bytes with
+the `bytes` option, such as "10MB" or "1.5GiB". The units "KB", "MB", "GB",
+and "TB" are decimal multiples of 1000, while "KiB", "MiB", "GiB", and "TiB"
+are binary multiples of 1024. Units are matched case-insensitively, and a
+number without a unit, or with the unit "B", is a number of bytes.
+
+Float fields may instead be parsed as percentages with the `percent` option,
+such as "75%", which is decoded as the fraction 0.75. The value must end in a
+"%" sign.
+
+Duration values must include a unit, such as "5s", by default. The `unit`
+option accepts bare integers as well, multiplying them by the named unit,
+which is one of "ns", "us", "ms", "s", "m", or "h"; with `unit=s`, both "5"
+and "5s" are decoded as five seconds. Values with an explicit unit always use
+that unit.
+
+Time values without zone information, such as those in the [time.DateOnly]
+layout, are interpreted in UTC by default. The `tz` option names a location
+to interpret them in instead, as loaded by [time.LoadLocation], such as
+`tz=America/New_York`. Values that include zone information are unaffected.
+
+# Bools
+
+Bool fields are parsed with [strconv.ParseBool] by default. The
+[ExtendedBool] option additionally accepts tokens such as "yes" and "off",
+and the [BoolParser] option replaces the parser entirely.
+
+Bool fields may instead be decoded from the mere presence of their key with
+the `presence` option: the field is true if the key is set at all, even to an
+empty value such as `DEBUG=`, and false otherwise. The value is never parsed.
+Since an unset key is a valid false value, a presence flag is always
+optional, and cannot be combined with `required`.
+
+# Validation
+
+String fields (and slices of strings) may be restricted to a fixed set of
+space-separated values with the `oneof` option, which reports a
+[ValidationError] for any other value. This check is performed after any case
+normalization, so `lower` may be combined with `oneof` to accept values
+case-insensitively.
+
+Numeric fields (and slices of numbers) may be bounded with the `min` and
+`max` options, which are both inclusive and may be floating point values.
+Values outside of these bounds are reported as a [ValidationError].
+
+Types may validate themselves by implementing [Validator]. The Validate
+method of each field that was set is called once it has been decoded, and the
+Validate method of each struct, including the output struct and each element
+of a slice of structs, is called once all of its fields have been decoded.
+Embedded structs are validated as part of the struct that embeds them,
+through its method set. Validation only runs if decoding succeeded, and any
+error is wrapped in a [ValidationError], so that it may still be retrieved
+with [errors.As].
+
+# Secrets
+
+Fields holding sensitive values may be marked with the `secret` option. The
+values of secret fields are never included in a [ParseError] or
+[ValidationError], and are omitted from the output of [Template].
+
+# Custom Options
+
+Any other tag option that is not built in, such as `yaml`, names a decoder
+supplied with the [WithTagDecoder] option, which decodes the value of the
+field in its place. This allows formats that would otherwise require a
+dependency to be supported by other packages.
+
+Using an option on a type it does not apply to, with an invalid argument, or
+in a conflicting combination, such as `csv` with `escape` or `min` greater
+than `max`, is an [InvalidTagOptionError], as is using a custom option without
+supplying its decoder.
 */
 package env
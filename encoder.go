@@ -0,0 +1,39 @@
+package env
+
+import (
+	"io"
+
+	"rodusek.dev/pkg/env/internal/dotenv"
+)
+
+// Encoder encodes config structs into environment variables, like [Marshal],
+// with a shared set of options. It is the counterpart to [Decoder].
+type Encoder struct {
+	opts []MarshalOption
+}
+
+// NewEncoder creates a new [Encoder] that applies the given options, such as
+// [NameFunc] or [OmitEmpty], to every value it encodes.
+func NewEncoder(opts ...MarshalOption) *Encoder {
+	return &Encoder{
+		opts: opts,
+	}
+}
+
+// Encode encodes the given config struct into an [Environment], as by
+// [Marshal], using the options of the encoder.
+func (e *Encoder) Encode(in any) (Environment, error) {
+	return Marshal(in, e.opts...)
+}
+
+// EncodeToWriter encodes the given config struct as by [Encoder.Encode], and
+// writes the result to the writer as dotenv text. Each variable is written on
+// its own line in ascending order of its key, and values are quoted where
+// necessary so that they are read back verbatim.
+func (e *Encoder) EncodeToWriter(w io.Writer, in any) error {
+	environment, err := e.Encode(in)
+	if err != nil {
+		return err
+	}
+	return dotenv.Write(w, environment.ToMap())
+}
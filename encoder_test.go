@@ -0,0 +1,99 @@
+package env_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"rodusek.dev/pkg/env"
+)
+
+func TestEncoderEncode(t *testing.T) {
+	type EncoderEnv struct {
+		Name     string        `env:"NAME,required"`
+		Timeout  time.Duration `env:"TIMEOUT"`
+		Tags     []string      `env:"TAGS"`
+		Optional *int          `env:"OPTIONAL"`
+	}
+	input := EncoderEnv{Name: "example", Timeout: time.Minute}
+
+	testCases := []struct {
+		name string
+		opts []env.MarshalOption
+		want env.Environment
+	}{
+		{
+			name: "No options",
+			want: env.Environment{"NAME": "example", "TIMEOUT": "1m0s", "TAGS": ""},
+		}, {
+			name: "OmitEmpty",
+			opts: []env.MarshalOption{env.OmitEmpty()},
+			want: env.Environment{"NAME": "example", "TIMEOUT": "1m0s"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := env.NewEncoder(tc.opts...)
+
+			got, err := sut.Encode(input)
+			if err != nil {
+				t.Fatalf("Encoder.Encode(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Encoder.Encode(%s): mismatch (-want +got):\n%s", tc.name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestEncoderEncode_NameFunc_AppliesToUntaggedFields(t *testing.T) {
+	input := struct {
+		ServerPort int
+	}{ServerPort: 8080}
+
+	got, err := env.NewEncoder(env.NameFunc(strings.ToLower)).Encode(input)
+	if err != nil {
+		t.Fatalf("Encoder.Encode(): unexpected error: %v", err)
+	}
+
+	if want := (env.Environment{"serverport": "8080"}); !cmp.Equal(got, want) {
+		t.Errorf("Encoder.Encode(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestEncoderEncodeToWriter(t *testing.T) {
+	type EncoderEnv struct {
+		Name    string   `env:"NAME"`
+		Greet   string   `env:"GREETING"`
+		Ports   []int    `env:"PORTS"`
+		Ignored []string `env:"IGNORED,omitempty"`
+	}
+	input := EncoderEnv{Name: "example", Greet: "hello \"world\"", Ports: []int{80, 443}}
+
+	var buf bytes.Buffer
+	if err := env.NewEncoder().EncodeToWriter(&buf, input); err != nil {
+		t.Fatalf("Encoder.EncodeToWriter(): unexpected error: %v", err)
+	}
+
+	want := "GREETING=\"hello \\\"world\\\"\"\nNAME=example\nPORTS=80,443\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Encoder.EncodeToWriter(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestEncoderEncodeToWriter_InvalidInput_ReturnsError(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := env.NewEncoder().EncodeToWriter(&buf, 42)
+
+	if err == nil {
+		t.Errorf("Encoder.EncodeToWriter(): got nil err, want error")
+	}
+	if got := buf.Len(); got != 0 {
+		t.Errorf("Encoder.EncodeToWriter(): wrote %v bytes, want 0", got)
+	}
+}
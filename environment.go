@@ -1,10 +1,13 @@
 package env
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -15,8 +18,9 @@ import (
 // used to unmarshal values without requiring the real environment to be
 // modified, such as through the dotenv sub-package.
 //
-// This type is not thread-safe. If you need to write to the access while
-// concurrently reading it, you should use a mutex to protect it.
+// This type is not thread-safe. If you need to write to the environment while
+// concurrently reading it, use [Environment.Synchronized] to obtain a
+// [SyncEnvironment].
 type Environment map[string]Value
 
 // Load the current environment variables into a new [Environment] instance.
@@ -37,6 +41,18 @@ func New() Environment {
 	return make(Environment)
 }
 
+// FromMap creates a new [Environment] containing the entries of the given map,
+// which is convenient for interoperating with libraries that represent
+// environments as a map[string]string. A nil map results in an empty, non-nil
+// environment.
+func FromMap(m map[string]string) Environment {
+	result := make(Environment, len(m))
+	for key, value := range m {
+		result[key] = Value(value)
+	}
+	return result
+}
+
 // Get the value of the environment variable with the given key, falling back
 // to the real environment as if by using [os.Getenv].
 //
@@ -64,8 +80,8 @@ func (e Environment) Lookup(key string) (value Value, ok bool) {
 		value, ok = e[key]
 	}
 	if !ok {
-		valueStr, ok := os.LookupEnv(key)
-		if !ok {
+		var valueStr string
+		if valueStr, ok = os.LookupEnv(key); !ok {
 			return "", false
 		}
 		value = Value(valueStr)
@@ -73,6 +89,36 @@ func (e Environment) Lookup(key string) (value Value, ok bool) {
 	return
 }
 
+// Getenv retrieves the value of the environment variable with the given key,
+// returning an empty string if it is not present. This is a drop-in
+// replacement for [os.Getenv].
+//
+// Unlike [Environment.Get], this never falls back to the real environment, so
+// it may be used to inject a hermetic environment into code that would
+// otherwise read the process environment. The recommended pattern is for such
+// code to accept a lookup function value:
+//
+//	type Server struct {
+//		Getenv func(key string) string // defaults to os.Getenv
+//	}
+//
+//	server := &Server{Getenv: env.Environment{"PORT": "8080"}.Getenv}
+func (e Environment) Getenv(key string) string {
+	return string(e[key])
+}
+
+// LookupEnv retrieves the value of the environment variable with the given
+// key, and whether it was present. This is a drop-in replacement for
+// [os.LookupEnv].
+//
+// Unlike [Environment.Lookup], this never falls back to the real environment.
+// See [Environment.Getenv] for the recommended pattern of passing this as a
+// function value.
+func (e Environment) LookupEnv(key string) (string, bool) {
+	value, ok := e[key]
+	return string(value), ok
+}
+
 // Set the value of the environment variable with the given key.
 func (e *Environment) Set(key string, value Value) {
 	if *e == nil {
@@ -81,6 +127,28 @@ func (e *Environment) Set(key string, value Value) {
 	(*e)[key] = value
 }
 
+// Expand replaces ${var} or $var in the string with the values of the
+// variables in this environment, falling back to the real environment as if by
+// using [Environment.Get]. This follows the semantics of [os.Expand]: unknown
+// variables expand to an empty string, and "$$" expands to "$".
+//
+// This is useful for rendering config file templates. To expand without
+// falling back to the real environment, use [SealedEnvironment.Expand].
+func (e Environment) Expand(s string) string {
+	return expand(s, e.Get)
+}
+
+// expand replaces ${var} or $var in the string using get, with "$$" expanding
+// to "$".
+func expand(s string, get func(key string) Value) string {
+	return os.Expand(s, func(key string) string {
+		if key == "$" {
+			return "$"
+		}
+		return string(get(key))
+	})
+}
+
 // Unset the environment variable with the given key.
 func (e Environment) Unset(key string) {
 	delete(e, key)
@@ -98,6 +166,194 @@ func (e Environment) Contains(key string) bool {
 	return ok
 }
 
+// Keys returns the keys of all variables in this environment, in no particular
+// order. The real environment is never consulted.
+func (e Environment) Keys() []string {
+	keys := make([]string, 0, len(e))
+	for key := range e {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// ToMap returns the entries of this environment as a new map[string]string,
+// which is convenient for interoperating with libraries that represent
+// environments this way. The real environment is never consulted. A nil
+// environment results in an empty, non-nil map.
+func (e Environment) ToMap() map[string]string {
+	result := make(map[string]string, len(e))
+	for key, value := range e {
+		result[key] = string(value)
+	}
+	return result
+}
+
+// MarshalJSON encodes this environment as a flat JSON object that maps each
+// key to its value as a JSON string, such as {"KEY":"value"}. A nil
+// environment is encoded as an empty object, rather than null. The real
+// environment is never consulted.
+func (e Environment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.ToMap())
+}
+
+// UnmarshalJSON decodes a flat JSON object of string values, as produced by
+// [Environment.MarshalJSON], replacing the contents of this environment. Values
+// that are not strings are an error. Like the standard library, a JSON null
+// leaves the environment unmodified.
+func (e *Environment) UnmarshalJSON(data []byte) error {
+	var entries map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	if entries != nil {
+		*e = FromMap(entries)
+	}
+	return nil
+}
+
+// Sorted returns the keys of all variables in this environment, sorted in
+// ascending order. This is useful for producing deterministic output, such as
+// for logging or diffing. The real environment is never consulted.
+func (e Environment) Sorted() []string {
+	keys := e.Keys()
+	sort.Strings(keys)
+	return keys
+}
+
+// Equal returns true if this environment contains exactly the same keys and
+// values as the other environment. The real environment is never consulted,
+// and nil and empty environments are considered equal.
+func (e Environment) Equal(other Environment) bool {
+	if len(e) != len(other) {
+		return false
+	}
+	for key, value := range e {
+		if otherValue, ok := other[key]; !ok || otherValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a new [Environment] containing the same variables as this
+// environment. Since values are immutable strings, this is a shallow copy, and
+// the result may be freely mutated without affecting the original.
+//
+// A nil environment results in an empty, non-nil environment.
+func (e Environment) Clone() Environment {
+	result := make(Environment, len(e))
+	for key, value := range e {
+		result[key] = value
+	}
+	return result
+}
+
+// Filter returns a new [Environment] containing only the variables for which
+// the keep function returns true. The original environment is left unmodified.
+//
+// Only the variables stored in this environment are considered; the real
+// environment is never consulted.
+func (e Environment) Filter(keep func(key string, value Value) bool) Environment {
+	result := make(Environment)
+	for key, value := range e {
+		if keep(key, value) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// WithPrefix returns a new [Environment] containing only the variables whose
+// keys begin with the given prefix, with that prefix stripped from the keys.
+// The original environment is left unmodified.
+//
+// For example, a prefix of "APP_" would map the variable "APP_NAME" to "NAME".
+func (e Environment) WithPrefix(prefix string) Environment {
+	result := make(Environment)
+	for key, value := range e {
+		if rest, ok := strings.CutPrefix(key, prefix); ok {
+			result[rest] = value
+		}
+	}
+	return result
+}
+
+// Sub returns a new [Environment] containing the variables of the section
+// named by prefix, with the prefix stripped from their keys, so that a struct
+// may be unmarshaled from the section without repeating the prefix in its
+// tags. This is useful for loading the same struct several times, such as once
+// per tenant.
+//
+// Unlike [Environment.WithPrefix], the prefix names a whole segment of the key:
+// a key only belongs to the section if the prefix is followed by an underscore,
+// which may be omitted from the prefix. This means that Sub("TENANT_1") maps
+// "TENANT_1_NAME" to "NAME", but excludes "TENANT_10_NAME". Keys that would be
+// empty once the prefix is stripped are excluded. An empty prefix results in a
+// copy of the whole environment.
+//
+// The result is a copy, rather than a live view: later changes to either
+// environment are not reflected in the other.
+func (e Environment) Sub(prefix string) Environment {
+	if prefix == "" {
+		return e.Clone()
+	}
+	if !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	result := e.WithPrefix(prefix)
+	delete(result, "")
+	return result
+}
+
+// Redacted returns a new [Environment] with the value of every variable for
+// which isSecret returns true replaced with "***". The original environment is
+// left unmodified.
+//
+// [DefaultSecretMatcher] may be used to redact common secret-like keys:
+//
+//	log.Print(environment.Redacted(env.DefaultSecretMatcher))
+func (e Environment) Redacted(isSecret func(key string) bool) Environment {
+	result := make(Environment, len(e))
+	for key, value := range e {
+		if isSecret(key) {
+			value = redacted
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// redacted is the value that secret values are replaced with.
+const redacted Value = "***"
+
+// DefaultSecretMatcher reports whether the key looks like it holds a secret,
+// which is any key containing "PASSWORD", "SECRET", "TOKEN", or "KEY",
+// compared case-insensitively.
+func DefaultSecretMatcher(key string) bool {
+	key = strings.ToUpper(key)
+	for _, word := range []string{"PASSWORD", "SECRET", "TOKEN", "KEY"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the environment as `KEY=value` lines ordered by key, with the
+// values of any keys matched by [DefaultSecretMatcher] redacted. This makes it
+// safe to accidentally print an environment, such as with [fmt.Println].
+//
+// The original environment is left unmodified; index the map directly to
+// access the unredacted values.
+func (e Environment) String() string {
+	redacted := e.Redacted(DefaultSecretMatcher)
+	lines := make([]string, 0, len(redacted))
+	for _, key := range redacted.Sorted() {
+		lines = append(lines, fmt.Sprintf("%s=%v", key, redacted[key]))
+	}
+	return strings.Join(lines, "\n")
+}
+
 // Export sets the environment variables in the current process.
 func (e Environment) Export() {
 	for key, value := range e {
@@ -105,22 +361,181 @@ func (e Environment) Export() {
 	}
 }
 
+// Apply exports the environment variables into the current process, runs fn,
+// and then restores the environment of the current process exactly as it was
+// before, even if fn panics. This is the equivalent of [testing.T.Setenv] for
+// arbitrary code paths.
+//
+// The whole process environment is restored, rather than only the variables
+// in this environment, so that any variables that fn itself sets, modifies, or
+// unsets are also restored. Since the process environment is global, Apply
+// must not be used concurrently with other code that reads or modifies it.
+func (e Environment) Apply(fn func()) {
+	snapshot := Load()
+	defer func() {
+		os.Clearenv()
+		snapshot.Export()
+	}()
+
+	e.Export()
+	fn()
+}
+
 // ExportCmd sets the environment variables into the specified subprocess
 // command object.
+//
+// This is additive: the variables are appended to any entries already present
+// in cmd.Env. Note that a nil cmd.Env causes the subprocess to inherit the
+// environment of the current process, so exporting an empty environment into a
+// command with a nil cmd.Env will leak every parent variable into the child.
+// Use [Environment.SetCmd] to control the child environment exactly.
 func (e Environment) ExportCmd(cmd *exec.Cmd) {
 	for key, value := range e {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%v", key, value))
 	}
 }
 
+// SetCmd sets the environment of the specified subprocess command object to
+// exactly the variables in this environment, replacing any existing entries in
+// cmd.Env.
+//
+// Unlike [Environment.ExportCmd], the subprocess never inherits the
+// environment of the current process: cmd.Env is always set to a non-nil
+// slice, even if this environment is empty. Entries are ordered by key.
+func (e Environment) SetCmd(cmd *exec.Cmd) {
+	keys := e.Sorted()
+
+	cmd.Env = make([]string, 0, len(keys))
+	for _, key := range keys {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%v", key, e[key]))
+	}
+}
+
 // Unmarshal the environment variables into the given struct.
 // See the documentation for [Unmarshal] for more details on what can be
 // returned from this function.
 func (e Environment) Unmarshal(out any, opts ...UnmarshalOption) error {
 	rv := reflect.ValueOf(out)
-	lookup := func(key string) (string, bool) {
-		value, ok := e.Lookup(key)
-		return string(value), ok
+	tag := newTagOptions(opts...)
+	lookup := e.lookupOr(tag.lookupOr(os.LookupEnv))
+	return tag.lookupFailed(decodeEnvironment(e, lookup, rv, opts...))
+}
+
+// UnmarshalSealed is like [Environment.Unmarshal], but looks up variables
+// strictly in this environment, without falling back to the real environment.
+// This makes decoding reproducible regardless of the variables set in the
+// current process, which is useful for tests and sandboxed config loading.
+// This is equivalent to converting the environment to a [SealedEnvironment]
+// before unmarshaling.
+func (e Environment) UnmarshalSealed(out any, opts ...UnmarshalOption) error {
+	return SealedEnvironment(e).Unmarshal(out, opts...)
+}
+
+// lookupOr returns a lookup that reads from the environment, and consults
+// fallback for any keys that are not present in it.
+func (e Environment) lookupOr(fallback lookup) lookup {
+	return func(key string) (string, bool) {
+		if value, ok := e[key]; ok {
+			return string(value), true
+		}
+		return fallback(key)
 	}
-	return decode(lookup, rv, opts...)
+}
+
+// UnmarshalContext is like [Environment.Unmarshal], but aborts decoding if the
+// given context is cancelled.
+// See the documentation for [UnmarshalContext] for more details.
+func (e Environment) UnmarshalContext(ctx context.Context, out any, opts ...UnmarshalOption) error {
+	return e.Unmarshal(out, withContext(ctx, opts)...)
+}
+
+// decodeEnvironment decodes into the given value from the lookup, and reports
+// any keys of the environment that were not consumed while decoding.
+func decodeEnvironment(keys keyLister, lookup lookup, rv reflect.Value, opts ...UnmarshalOption) error {
+	opts = withKeys(keys, opts)
+	consumed := make(map[string]struct{})
+	tracked := func(key string) (string, bool) {
+		consumed[key] = struct{}{}
+		return lookup(key)
+	}
+	if err := decode(tracked, rv, opts...); err != nil {
+		return err
+	}
+
+	tag := newTagOptions(opts...)
+	var unused []string
+	for _, key := range tag.keysWithPrefix("") {
+		if _, ok := consumed[key]; !ok {
+			unused = append(unused, key)
+		}
+	}
+
+	if tag.disallowUnknown && len(unused) > 0 {
+		return &UnknownKeyError{
+			Keys: unused,
+		}
+	}
+	if tag.unused != nil {
+		*tag.unused = unused
+	}
+	return nil
+}
+
+// GetFrom retrieves the value of the environment variable with the given key
+// from the environment and unmarshals it into the provided type. This is the
+// equivalent of [Get] for an arbitrary [Environment], rather than the real
+// environment. Like [Environment.Lookup], keys that are not present in e are
+// looked up in the real environment. Use [GetFromEnv] to consult only e.
+//
+// This function will only return errors if the environment variable is not set
+// or if the value cannot be unmarshaled into the provided type correctly.
+func GetFrom[T any](e Environment, key string) (got T, err error) {
+	value, ok := e.Lookup(key)
+	if !ok {
+		err = &RequirementError{
+			Key:  key,
+			Type: reflect.TypeFor[T](),
+		}
+		return
+	}
+	err = value.Decode(&got)
+	return
+}
+
+// GetFromEnv retrieves the value of the environment variable with the given
+// key from the environment and unmarshals it into the provided type. Unlike
+// [GetFrom], only e is consulted, as if it were a [SealedEnvironment]; keys
+// that are not present in e are treated as unset even if they are set in the
+// real environment. This keeps lookups hermetic, such as when injecting an
+// environment in tests.
+//
+// This function will only return errors if the environment variable is not set
+// or if the value cannot be unmarshaled into the provided type correctly.
+func GetFromEnv[T any](e Environment, name string) (got T, err error) {
+	value, ok := e[name]
+	if !ok {
+		err = &RequirementError{
+			Key:  name,
+			Type: reflect.TypeFor[T](),
+		}
+		return
+	}
+	err = value.Decode(&got)
+	return
+}
+
+// GetOrFromEnv retrieves the value of the environment variable with the given
+// key from the environment and unmarshals it into the provided type. If the
+// key is not present in e, the fallback value is returned instead. Like
+// [GetFromEnv], the real environment is never consulted.
+//
+// This function will only return errors if the value cannot be unmarshaled into
+// the provided type correctly.
+func GetOrFromEnv[T any](e Environment, name string, fallback T) (got T, err error) {
+	value, ok := e[name]
+	if !ok {
+		return fallback, nil
+	}
+	err = value.Decode(&got)
+	return
 }
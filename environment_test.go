@@ -1,7 +1,14 @@
 package env_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -370,3 +377,1333 @@ func setEnvironment(e env.Environment, str string, args ...any) {
 		e.Set(key, env.Value(value))
 	}
 }
+
+func TestEnvironmentKeys(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "process")
+	testCases := []struct {
+		name string
+		sut  env.Environment
+		want []string
+	}{
+		{
+			name: "Nil environment",
+			sut:  nil,
+			want: []string{},
+		}, {
+			name: "Multiple keys",
+			sut: env.Environment{
+				"PATH":  "/usr/bin",
+				"HOME":  "/home/user",
+				"SHELL": "/bin/sh",
+			},
+			want: []string{"HOME", "PATH", "SHELL"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.sut.Keys()
+			sort.Strings(got)
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Environment.Keys(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentSorted(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "process")
+	testCases := []struct {
+		name string
+		sut  env.Environment
+		want []string
+	}{
+		{
+			name: "Nil environment",
+			sut:  nil,
+			want: []string{},
+		}, {
+			name: "Multiple keys",
+			sut: env.Environment{
+				"PATH":  "/usr/bin",
+				"HOME":  "/home/user",
+				"SHELL": "/bin/sh",
+			},
+			want: []string{"HOME", "PATH", "SHELL"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.sut.Sorted()
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Environment.Sorted(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentRedacted(t *testing.T) {
+	sut := env.Environment{
+		"DB_PASSWORD": "hunter2",
+		"API_KEY":     "abc123",
+		"HOST":        "localhost",
+	}
+	original := maps.Clone(sut)
+
+	got := sut.Redacted(env.DefaultSecretMatcher)
+
+	want := env.Environment{
+		"DB_PASSWORD": "***",
+		"API_KEY":     "***",
+		"HOST":        "localhost",
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Environment.Redacted(): got '%v', want '%v'", map[string]env.Value(got), map[string]env.Value(want))
+	}
+	if got, want := sut, original; !cmp.Equal(got, want) {
+		t.Errorf("Environment.Redacted(): modified original")
+	}
+}
+
+func TestDefaultSecretMatcher(t *testing.T) {
+	testCases := []struct {
+		key  string
+		want bool
+	}{
+		{key: "DB_PASSWORD", want: true},
+		{key: "client_secret", want: true},
+		{key: "GITHUB_TOKEN", want: true},
+		{key: "API_KEY", want: true},
+		{key: "HOST", want: false},
+		{key: "PORT", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			if got, want := env.DefaultSecretMatcher(tc.key), tc.want; got != want {
+				t.Errorf("DefaultSecretMatcher(%s): got '%v', want '%v'", tc.key, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentString_RedactsSecrets(t *testing.T) {
+	sut := env.Environment{
+		"HOST":        "localhost",
+		"DB_PASSWORD": "hunter2",
+	}
+
+	got := fmt.Sprint(sut)
+
+	if want := "DB_PASSWORD=***\nHOST=localhost"; got != want {
+		t.Errorf("Environment.String(): got '%v', want '%v'", got, want)
+	}
+	if got, want := sut["DB_PASSWORD"], env.Value("hunter2"); got != want {
+		t.Errorf("Environment.String(): modified original value to '%v', want '%v'", got, want)
+	}
+}
+
+func TestEnvironmentClone(t *testing.T) {
+	testCases := []struct {
+		name string
+		sut  env.Environment
+		want env.Environment
+	}{
+		{
+			name: "Nil environment",
+			sut:  nil,
+			want: env.Environment{},
+		}, {
+			name: "Copies all keys",
+			sut: env.Environment{
+				"HOME": "/home/user",
+				"PATH": "/usr/bin",
+			},
+			want: env.Environment{
+				"HOME": "/home/user",
+				"PATH": "/usr/bin",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.sut.Clone()
+
+			if got == nil {
+				t.Fatalf("Environment.Clone(%s): got nil, want non-nil", tc.name)
+			}
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Environment.Clone(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentClone_MutatingClone_LeavesOriginal(t *testing.T) {
+	sut := env.Environment{"HOME": "/home/user"}
+
+	clone := sut.Clone()
+	clone.Set("HOME", "/root")
+	clone.Set("PATH", "/usr/bin")
+
+	if got, want := sut, (env.Environment{"HOME": "/home/user"}); !cmp.Equal(got, want) {
+		t.Errorf("Environment.Clone(): modified original to '%v', want '%v'", got, want)
+	}
+}
+
+func TestEnvironmentEqual(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "value")
+
+	testCases := []struct {
+		name  string
+		sut   env.Environment
+		other env.Environment
+		want  bool
+	}{
+		{
+			name:  "Nil and empty",
+			sut:   nil,
+			other: env.Environment{},
+			want:  true,
+		}, {
+			name:  "Same entries",
+			sut:   env.Environment{"HOME": "/home/user", "PATH": "/usr/bin"},
+			other: env.Environment{"PATH": "/usr/bin", "HOME": "/home/user"},
+			want:  true,
+		}, {
+			name:  "Different values",
+			sut:   env.Environment{"HOME": "/home/user"},
+			other: env.Environment{"HOME": "/root"},
+			want:  false,
+		}, {
+			name:  "Different keys",
+			sut:   env.Environment{"HOME": "/home/user"},
+			other: env.Environment{"USER": "/home/user"},
+			want:  false,
+		}, {
+			name:  "Different lengths",
+			sut:   env.Environment{"HOME": "/home/user"},
+			other: env.Environment{"HOME": "/home/user", "PATH": "/usr/bin"},
+			want:  false,
+		}, {
+			name:  "Key only in process environment",
+			sut:   env.Environment{},
+			other: env.Environment{"PROCESS_ONLY": "value"},
+			want:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.sut.Equal(tc.other)
+
+			if want := tc.want; got != want {
+				t.Errorf("Environment.Equal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := tc.other.Equal(tc.sut), tc.want; got != want {
+				t.Errorf("Environment.Equal(%s): got '%v' when reversed, want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentFilter(t *testing.T) {
+	testCases := []struct {
+		name string
+		sut  env.Environment
+		keep func(key string, value env.Value) bool
+		want env.Environment
+	}{
+		{
+			name: "Nil environment",
+			sut:  nil,
+			keep: func(string, env.Value) bool { return true },
+			want: env.Environment{},
+		}, {
+			name: "Keeps matching keys",
+			sut: env.Environment{
+				"HOME":  "/home/user",
+				"PATH":  "/usr/bin",
+				"SHELL": "/bin/sh",
+			},
+			keep: func(key string, _ env.Value) bool { return key != "SHELL" },
+			want: env.Environment{
+				"HOME": "/home/user",
+				"PATH": "/usr/bin",
+			},
+		}, {
+			name: "Keeps matching values",
+			sut: env.Environment{
+				"EMPTY":    "",
+				"NONEMPTY": "value",
+			},
+			keep: func(_ string, value env.Value) bool { return value != "" },
+			want: env.Environment{
+				"NONEMPTY": "value",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := maps.Clone(tc.sut)
+
+			got := tc.sut.Filter(tc.keep)
+
+			if got == nil {
+				t.Fatalf("Environment.Filter(%s): got nil, want non-nil", tc.name)
+			}
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Environment.Filter(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := tc.sut, original; !cmp.Equal(got, want) {
+				t.Errorf("Environment.Filter(%s): modified original to '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentWithPrefix(t *testing.T) {
+	testCases := []struct {
+		name   string
+		sut    env.Environment
+		prefix string
+		want   env.Environment
+	}{
+		{
+			name:   "Nil environment",
+			sut:    nil,
+			prefix: "APP_",
+			want:   env.Environment{},
+		}, {
+			name: "Keeps and strips prefix",
+			sut: env.Environment{
+				"APP_NAME": "example",
+				"APP_PORT": "8080",
+				"HOME":     "/home/user",
+			},
+			prefix: "APP_",
+			want: env.Environment{
+				"NAME": "example",
+				"PORT": "8080",
+			},
+		}, {
+			name: "Empty prefix keeps everything",
+			sut: env.Environment{
+				"APP_NAME": "example",
+				"HOME":     "/home/user",
+			},
+			prefix: "",
+			want: env.Environment{
+				"APP_NAME": "example",
+				"HOME":     "/home/user",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := maps.Clone(tc.sut)
+
+			got := tc.sut.WithPrefix(tc.prefix)
+
+			if got == nil {
+				t.Fatalf("Environment.WithPrefix(%s): got nil, want non-nil", tc.name)
+			}
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Environment.WithPrefix(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := tc.sut, original; !cmp.Equal(got, want) {
+				t.Errorf("Environment.WithPrefix(%s): modified original to '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentSub(t *testing.T) {
+	tenants := env.Environment{
+		"TENANT_1_NAME":   "one",
+		"TENANT_1_PORT":   "8001",
+		"TENANT_10_NAME":  "ten",
+		"TENANT_1":        "bare",
+		"TENANT_1_":       "empty key",
+		"TENANT_1_DB_URL": "postgres://one",
+		"HOME":            "/home/user",
+	}
+
+	testCases := []struct {
+		name   string
+		sut    env.Environment
+		prefix string
+		want   env.Environment
+	}{
+		{
+			name:   "Nil environment",
+			sut:    nil,
+			prefix: "TENANT_1",
+			want:   env.Environment{},
+		}, {
+			name:   "Excludes overlapping prefixes",
+			sut:    tenants,
+			prefix: "TENANT_1",
+			want: env.Environment{
+				"NAME":   "one",
+				"PORT":   "8001",
+				"DB_URL": "postgres://one",
+			},
+		}, {
+			name:   "Trailing underscore is optional",
+			sut:    tenants,
+			prefix: "TENANT_1_",
+			want: env.Environment{
+				"NAME":   "one",
+				"PORT":   "8001",
+				"DB_URL": "postgres://one",
+			},
+		}, {
+			name:   "Longer overlapping prefix",
+			sut:    tenants,
+			prefix: "TENANT_10",
+			want:   env.Environment{"NAME": "ten"},
+		}, {
+			name:   "Nested section",
+			sut:    tenants,
+			prefix: "TENANT_1_DB",
+			want:   env.Environment{"URL": "postgres://one"},
+		}, {
+			name:   "Empty prefix copies everything",
+			sut:    env.Environment{"HOME": "/home/user"},
+			prefix: "",
+			want:   env.Environment{"HOME": "/home/user"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := maps.Clone(tc.sut)
+
+			got := tc.sut.Sub(tc.prefix)
+
+			if got == nil {
+				t.Fatalf("Environment.Sub(%s): got nil, want non-nil", tc.name)
+			}
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Environment.Sub(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := tc.sut, original; !cmp.Equal(got, want) {
+				t.Errorf("Environment.Sub(%s): modified original to '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentSub_IsACopy(t *testing.T) {
+	sut := env.Environment{"TENANT_1_NAME": "one"}
+
+	sub := sut.Sub("TENANT_1")
+	sub["NAME"] = "changed"
+	sut["TENANT_1_PORT"] = "8001"
+
+	if got, want := sut["TENANT_1_NAME"], env.Value("one"); got != want {
+		t.Errorf("Environment.Sub(): got original '%v', want '%v'", got, want)
+	}
+	if _, ok := sub["PORT"]; ok {
+		t.Errorf("Environment.Sub(): got later change reflected in copy, want it absent")
+	}
+}
+
+func TestEnvironmentSub_Unmarshal(t *testing.T) {
+	type Tenant struct {
+		Name string `env:"NAME,required"`
+		Port int    `env:"PORT"`
+	}
+	sut := env.Environment{
+		"TENANT_1_NAME":  "one",
+		"TENANT_1_PORT":  "8001",
+		"TENANT_10_NAME": "ten",
+	}
+
+	var got Tenant
+	if err := sut.Sub("TENANT_10").UnmarshalSealed(&got); err != nil {
+		t.Fatalf("Environment.Sub().UnmarshalSealed(): unexpected error: %v", err)
+	}
+
+	if want := (Tenant{Name: "ten"}); !cmp.Equal(got, want) {
+		t.Errorf("Environment.Sub().UnmarshalSealed(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestEnvironmentSetCmd(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sut      env.Environment
+		existing []string
+		want     []string
+	}{
+		{
+			name: "Nil environment",
+			sut:  nil,
+			want: []string{},
+		}, {
+			name:     "Replaces existing entries",
+			sut:      env.Environment{"B": "2", "A": "1"},
+			existing: []string{"PARENT=leaked"},
+			want:     []string{"A=1", "B=2"},
+		}, {
+			name: "Sets entries on nil cmd.Env",
+			sut:  env.Environment{"KEY": "value"},
+			want: []string{"KEY=value"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command("true")
+			cmd.Env = tc.existing
+
+			tc.sut.SetCmd(cmd)
+
+			if cmd.Env == nil {
+				t.Fatalf("Environment.SetCmd(%s): got nil cmd.Env, want non-nil", tc.name)
+			}
+			if got, want := cmd.Env, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Environment.SetCmd(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentApply(t *testing.T) {
+	t.Setenv("APPLY_MODIFIED", "original")
+	t.Setenv("APPLY_DELETED", "original")
+	before := env.Load()
+	sut := env.Environment{
+		"APPLY_MODIFIED": "applied",
+		"APPLY_ADDED":    "applied",
+	}
+
+	var during env.Environment
+	sut.Apply(func() {
+		during = env.Load()
+		os.Unsetenv("APPLY_DELETED")
+		os.Setenv("APPLY_CREATED", "created")
+	})
+
+	if got, want := during["APPLY_MODIFIED"], env.Value("applied"); got != want {
+		t.Errorf("Environment.Apply(): got '%v' during fn, want '%v'", got, want)
+	}
+	if got, want := during["APPLY_ADDED"], env.Value("applied"); got != want {
+		t.Errorf("Environment.Apply(): got '%v' during fn, want '%v'", got, want)
+	}
+	if got, want := env.Load(), before; !cmp.Equal(got, want) {
+		t.Errorf("Environment.Apply(): environment not restored (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestEnvironmentApply_Panics_RestoresEnvironment(t *testing.T) {
+	t.Setenv("APPLY_MODIFIED", "original")
+	before := env.Load()
+	sut := env.Environment{"APPLY_MODIFIED": "applied"}
+
+	func() {
+		defer func() { _ = recover() }()
+		sut.Apply(func() {
+			panic("failure")
+		})
+	}()
+
+	if got, want := env.Load(), before; !cmp.Equal(got, want) {
+		t.Errorf("Environment.Apply(): environment not restored (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestEnvironmentExportCmd(t *testing.T) {
+	sut := env.Environment{"B": "2", "A": "1"}
+	cmd := exec.Command("true")
+	cmd.Env = []string{"EXISTING=value"}
+
+	sut.ExportCmd(cmd)
+
+	got := cmd.Env
+	sort.Strings(got)
+	if want := []string{"A=1", "B=2", "EXISTING=value"}; !cmp.Equal(got, want) {
+		t.Errorf("Environment.ExportCmd(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestEnvironmentGetenv(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "process")
+	sut := env.Environment{
+		"KEY":   "value",
+		"EMPTY": "",
+	}
+
+	testCases := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{
+			name: "Present key",
+			key:  "KEY",
+			want: "value",
+		}, {
+			name: "Empty key",
+			key:  "EMPTY",
+			want: "",
+		}, {
+			name: "Missing key",
+			key:  "MISSING",
+			want: "",
+		}, {
+			name: "Process-only key does not fall back",
+			key:  "PROCESS_ONLY",
+			want: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var getenv func(string) string = sut.Getenv
+
+			if got, want := getenv(tc.key), tc.want; got != want {
+				t.Errorf("Environment.Getenv(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentLookupEnv(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "process")
+	sut := env.Environment{
+		"KEY":   "value",
+		"EMPTY": "",
+	}
+
+	testCases := []struct {
+		name   string
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "Present key",
+			key:    "KEY",
+			want:   "value",
+			wantOK: true,
+		}, {
+			name:   "Empty key",
+			key:    "EMPTY",
+			want:   "",
+			wantOK: true,
+		}, {
+			name: "Missing key",
+			key:  "MISSING",
+		}, {
+			name: "Process-only key does not fall back",
+			key:  "PROCESS_ONLY",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var lookupEnv func(string) (string, bool) = sut.LookupEnv
+
+			got, ok := lookupEnv(tc.key)
+
+			if got, want := got, tc.want; got != want {
+				t.Errorf("Environment.LookupEnv(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := ok, tc.wantOK; got != want {
+				t.Errorf("Environment.LookupEnv(%s): got ok '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentUnmarshal_ReportUnused(t *testing.T) {
+	type ReportEnv struct {
+		Name string `env:"NAME"`
+		Port int    `env:"PORT"`
+	}
+
+	testCases := []struct {
+		name string
+		sut  env.Environment
+		want []string
+	}{
+		{
+			name: "All keys consumed",
+			sut:  env.Environment{"NAME": "example", "PORT": "8080"},
+			want: nil,
+		}, {
+			name: "Unconsumed keys reported in order",
+			sut:  env.Environment{"NAME": "example", "PROT": "8080", "NAEM": "typo"},
+			want: []string{"NAEM", "PROT"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var unused []string
+			var out ReportEnv
+			err := tc.sut.Unmarshal(&out, env.ReportUnused(&unused))
+			if err != nil {
+				t.Fatalf("Environment.Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := unused, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Environment.Unmarshal(%s): got unused '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_ReportUnused_IsIgnored(t *testing.T) {
+	type ReportEnv struct {
+		Name string `env:"NAME"`
+	}
+	setenv(t, "UNRELATED=value")
+
+	unused := []string{"untouched"}
+	var out ReportEnv
+	err := env.Unmarshal(&out, env.ReportUnused(&unused))
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := unused, []string{"untouched"}; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got unused '%v', want '%v'", got, want)
+	}
+}
+
+func TestEnvironmentUnmarshal_DisallowUnknownKeys(t *testing.T) {
+	type StrictEnv struct {
+		Name string `env:"NAME"`
+		Port int    `env:"PORT"`
+	}
+
+	testCases := []struct {
+		name     string
+		sut      env.Environment
+		want     StrictEnv
+		wantKeys []string
+	}{
+		{
+			name: "All keys consumed",
+			sut:  env.Environment{"NAME": "example", "PORT": "8080"},
+			want: StrictEnv{Name: "example", Port: 8080},
+		}, {
+			name:     "Unknown keys",
+			sut:      env.Environment{"NAME": "example", "PROT": "8080", "NAEM": "typo"},
+			wantKeys: []string{"NAEM", "PROT"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out StrictEnv
+			err := tc.sut.Unmarshal(&out, env.DisallowUnknownKeys())
+
+			if tc.wantKeys == nil {
+				if err != nil {
+					t.Fatalf("Environment.Unmarshal(%s): unexpected error: %v", tc.name, err)
+				}
+				if got, want := out, tc.want; !cmp.Equal(got, want) {
+					t.Errorf("Environment.Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+				}
+				return
+			}
+
+			var unknownErr *env.UnknownKeyError
+			if !errors.As(err, &unknownErr) {
+				t.Fatalf("Environment.Unmarshal(%s): expected UnknownKeyError, got %T", tc.name, err)
+			}
+			if got, want := unknownErr.Keys, tc.wantKeys; !cmp.Equal(got, want) {
+				t.Errorf("Environment.Unmarshal(%s): got keys '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_DisallowUnknownKeys_IsIgnored(t *testing.T) {
+	type StrictEnv struct {
+		Name string `env:"NAME"`
+	}
+	setenv(t, "UNRELATED=value")
+
+	var out StrictEnv
+	err := env.Unmarshal(&out, env.DisallowUnknownKeys())
+
+	if err != nil {
+		t.Errorf("Unmarshal(): unexpected error: %v", err)
+	}
+}
+
+func TestEnvironmentUnmarshal_StructSlice(t *testing.T) {
+	type Upstream struct {
+		Host string `env:"HOST,required"`
+		Port int    `env:"PORT"`
+	}
+	type UpstreamEnv struct {
+		Upstreams []Upstream  `env:"UPSTREAM"`
+		Pointers  []*Upstream `env:"POINTER"`
+	}
+
+	testCases := []struct {
+		name        string
+		environment string
+		want        UpstreamEnv
+	}{
+		{
+			name:        "No upstreams",
+			environment: "",
+			want:        UpstreamEnv{},
+		}, {
+			name: "Contiguous upstreams",
+			environment: `
+				UPSTREAM_0_HOST=a.example.com
+				UPSTREAM_0_PORT=80
+				UPSTREAM_1_HOST=b.example.com
+			`,
+			want: UpstreamEnv{
+				Upstreams: []Upstream{
+					{Host: "a.example.com", Port: 80},
+					{Host: "b.example.com"},
+				},
+			},
+		}, {
+			name: "Stops at first gap",
+			environment: `
+				UPSTREAM_0_HOST=a.example.com
+				UPSTREAM_2_HOST=c.example.com
+			`,
+			want: UpstreamEnv{
+				Upstreams: []Upstream{
+					{Host: "a.example.com"},
+				},
+			},
+		}, {
+			name: "Pointer elements",
+			environment: `
+				POINTER_0_HOST=a.example.com
+			`,
+			want: UpstreamEnv{
+				Pointers: []*Upstream{
+					{Host: "a.example.com"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := env.Environment{}
+			setEnvironment(sut, tc.environment)
+
+			var out UpstreamEnv
+			err := sut.Unmarshal(&out)
+			if err != nil {
+				t.Fatalf("Environment.Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := out, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Environment.Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentUnmarshal_StructSliceMissingRequired_ReturnsPrefixedKey(t *testing.T) {
+	type Upstream struct {
+		Host string `env:"HOST,required"`
+		Port int    `env:"PORT"`
+	}
+	type UpstreamEnv struct {
+		Upstreams []Upstream `env:"UPSTREAM,required"`
+	}
+
+	testCases := []struct {
+		name        string
+		environment string
+		wantKey     string
+	}{
+		{
+			name:        "Missing element field",
+			environment: "UPSTREAM_0_PORT=80",
+			wantKey:     "UPSTREAM_0_HOST",
+		}, {
+			name:        "Missing required slice",
+			environment: "",
+			wantKey:     "UPSTREAM",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := env.Environment{}
+			setEnvironment(sut, tc.environment)
+
+			var out UpstreamEnv
+			err := sut.Unmarshal(&out)
+
+			var requiredErr *env.RequirementError
+			if !errors.As(err, &requiredErr) {
+				t.Fatalf("Environment.Unmarshal(%s): expected RequirementError, got %T", tc.name, err)
+			}
+			if got, want := requiredErr.Key, tc.wantKey; got != want {
+				t.Errorf("Environment.Unmarshal(%s): got key '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentUnmarshalContext_Cancelled_ReturnsError(t *testing.T) {
+	type ContextEnv struct {
+		Name string `env:"NAME"`
+	}
+	sut := env.Environment{"NAME": "example"}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out ContextEnv
+	err := sut.UnmarshalContext(ctx, &out)
+
+	if got, want := err, context.Canceled; !errors.Is(got, want) {
+		t.Errorf("Environment.UnmarshalContext(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestEnvironmentUnmarshalContext_DeadlineExceeded_ReturnsError(t *testing.T) {
+	type ContextEnv struct {
+		Name string `env:"NAME"`
+	}
+	sut := env.Environment{"NAME": "example"}
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	var out ContextEnv
+	err := sut.UnmarshalContext(ctx, &out)
+
+	if got, want := err, context.DeadlineExceeded; !errors.Is(got, want) {
+		t.Errorf("Environment.UnmarshalContext(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestEnvironmentUnmarshal_WithLookup_EnvironmentTakesPrecedence(t *testing.T) {
+	type LookupEnv struct {
+		Name   string `env:"NAME"`
+		Port   int    `env:"PORT"`
+		Region string `env:"REGION"`
+	}
+	t.Setenv("REGION", "from-os")
+	lookup := func(key string) (string, bool) {
+		if key == "REGION" {
+			return "", false
+		}
+		return "42", true
+	}
+	testCases := []struct {
+		name      string
+		unmarshal func(out any, opts ...env.UnmarshalOption) error
+	}{
+		{
+			name:      "Environment",
+			unmarshal: env.Environment{"NAME": "from-map"}.Unmarshal,
+		}, {
+			name:      "SealedEnvironment",
+			unmarshal: env.SealedEnvironment{"NAME": "from-map"}.Unmarshal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out LookupEnv
+			err := tc.unmarshal(&out, env.WithLookup(lookup))
+			if err != nil {
+				t.Fatalf("%s.Unmarshal(): unexpected error: %v", tc.name, err)
+			}
+
+			want := LookupEnv{Name: "from-map", Port: 42}
+			if got := out; !cmp.Equal(got, want) {
+				t.Errorf("%s.Unmarshal(): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentUnmarshalSealed_IgnoresProcessEnvironment(t *testing.T) {
+	type SealedEnv struct {
+		Name         string `env:"NAME"`
+		ProcessOnly  string `env:"PROCESS_ONLY"`
+		RequiredOnly string `env:"REQUIRED_ONLY,required"`
+	}
+	t.Setenv("PROCESS_ONLY", "process")
+	t.Setenv("REQUIRED_ONLY", "process")
+	sut := env.Environment{"NAME": "from-map"}
+
+	var out SealedEnv
+	err := sut.UnmarshalSealed(&out)
+
+	if got, want := err, env.ErrRequirement; !errors.Is(got, want) {
+		t.Fatalf("Environment.UnmarshalSealed(): got err '%v', want '%v'", got, want)
+	}
+	if got, want := out.ProcessOnly, ""; got != want {
+		t.Errorf("Environment.UnmarshalSealed(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestEnvironmentUnmarshalSealed_ReadsEnvironment(t *testing.T) {
+	type SealedEnv struct {
+		Name        string `env:"NAME"`
+		ProcessOnly string `env:"PROCESS_ONLY"`
+	}
+	t.Setenv("PROCESS_ONLY", "process")
+	sut := env.Environment{"NAME": "from-map"}
+
+	var out SealedEnv
+	if err := sut.UnmarshalSealed(&out); err != nil {
+		t.Fatalf("Environment.UnmarshalSealed(): unexpected error: %v", err)
+	}
+
+	want := SealedEnv{Name: "from-map"}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Environment.UnmarshalSealed(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestGetFrom(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "7")
+	sut := env.Environment{"PORT": "8080", "INVALID": "not-a-number"}
+
+	testCases := []struct {
+		name    string
+		key     string
+		want    int
+		wantErr error
+	}{
+		{
+			name: "Key in environment",
+			key:  "PORT",
+			want: 8080,
+		}, {
+			name: "Key in process environment",
+			key:  "PROCESS_ONLY",
+			want: 7,
+		}, {
+			name:    "Missing key",
+			key:     "MISSING",
+			wantErr: env.ErrRequirement,
+		}, {
+			name:    "Invalid value",
+			key:     "INVALID",
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := env.GetFrom[int](sut, tc.key)
+
+			if got, want := err, tc.wantErr; !errors.Is(got, want) && !(got == nil && want == nil) {
+				t.Fatalf("GetFrom(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := got, tc.want; got != want {
+				t.Errorf("GetFrom(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestGetFromEnv(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "7")
+	sut := env.Environment{"PORT": "8080", "INVALID": "not-a-number"}
+
+	testCases := []struct {
+		name    string
+		key     string
+		want    int
+		wantErr error
+	}{
+		{
+			name: "Key in environment",
+			key:  "PORT",
+			want: 8080,
+		}, {
+			name:    "Key only in process environment",
+			key:     "PROCESS_ONLY",
+			wantErr: env.ErrRequirement,
+		}, {
+			name:    "Missing key",
+			key:     "MISSING",
+			wantErr: env.ErrRequirement,
+		}, {
+			name:    "Invalid value",
+			key:     "INVALID",
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := env.GetFromEnv[int](sut, tc.key)
+
+			if got, want := err, tc.wantErr; !errors.Is(got, want) && !(got == nil && want == nil) {
+				t.Fatalf("GetFromEnv(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := got, tc.want; got != want {
+				t.Errorf("GetFromEnv(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestGetOrFromEnv(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "7")
+	sut := env.Environment{"PORT": "8080", "INVALID": "not-a-number"}
+
+	testCases := []struct {
+		name    string
+		key     string
+		want    int
+		wantErr error
+	}{
+		{
+			name: "Key in environment",
+			key:  "PORT",
+			want: 8080,
+		}, {
+			name: "Key only in process environment",
+			key:  "PROCESS_ONLY",
+			want: 42,
+		}, {
+			name: "Missing key",
+			key:  "MISSING",
+			want: 42,
+		}, {
+			name:    "Invalid value",
+			key:     "INVALID",
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := env.GetOrFromEnv(sut, tc.key, 42)
+
+			if got, want := err, tc.wantErr; !errors.Is(got, want) && !(got == nil && want == nil) {
+				t.Fatalf("GetOrFromEnv(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := got, tc.want; got != want {
+				t.Errorf("GetOrFromEnv(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentLookup_ProcessFallback_ReportsFound(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "process")
+	sut := env.Environment{}
+
+	value, ok := sut.Lookup("PROCESS_ONLY")
+
+	if !ok {
+		t.Fatalf("Environment.Lookup(): got ok 'false', want 'true'")
+	}
+	if got, want := value, env.Value("process"); got != want {
+		t.Errorf("Environment.Lookup(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestEnvironmentExpand(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "process")
+	sut := env.Environment{"HOST": "localhost", "PORT": "8080"}
+
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Braced variables", input: "http://${HOST}:${PORT}/", want: "http://localhost:8080/"},
+		{name: "Bare variables", input: "$HOST:$PORT", want: "localhost:8080"},
+		{name: "Unknown variable", input: "[$MISSING]", want: "[]"},
+		{name: "Escaped dollar", input: "cost: $$5", want: "cost: $5"},
+		{name: "Process fallback", input: "${PROCESS_ONLY}", want: "process"},
+		{name: "No variables", input: "plain", want: "plain"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := sut.Expand(tc.input), tc.want; got != want {
+				t.Errorf("Environment.Expand(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input map[string]string
+		want  env.Environment
+	}{
+		{
+			name:  "Nil map",
+			input: nil,
+			want:  env.Environment{},
+		}, {
+			name:  "Entries",
+			input: map[string]string{"HOST": "localhost", "PORT": "8080"},
+			want:  env.Environment{"HOST": "localhost", "PORT": "8080"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := env.FromMap(tc.input)
+
+			if got == nil {
+				t.Fatalf("FromMap(%s): got nil, want non-nil", tc.name)
+			}
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("FromMap(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentToMap(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input env.Environment
+		want  map[string]string
+	}{
+		{
+			name:  "Nil environment",
+			input: nil,
+			want:  map[string]string{},
+		}, {
+			name:  "Entries",
+			input: env.Environment{"HOST": "localhost", "PORT": "8080"},
+			want:  map[string]string{"HOST": "localhost", "PORT": "8080"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.input.ToMap()
+
+			if got == nil {
+				t.Fatalf("Environment.ToMap(%s): got nil, want non-nil", tc.name)
+			}
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Environment.ToMap(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentMarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input env.Environment
+		want  string
+	}{
+		{
+			name:  "Nil",
+			input: nil,
+			want:  `{}`,
+		}, {
+			name:  "Empty",
+			input: env.Environment{},
+			want:  `{}`,
+		}, {
+			name:  "Entries",
+			input: env.Environment{"PORT": "8080", "HOST": "localhost"},
+			want:  `{"HOST":"localhost","PORT":"8080"}`,
+		}, {
+			name:  "Special characters",
+			input: env.Environment{"QUOTE": `say "hi"`},
+			want:  `{"QUOTE":"say \"hi\""}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(tc.input)
+			if err != nil {
+				t.Fatalf("Environment.MarshalJSON(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := string(got), tc.want; got != want {
+				t.Errorf("Environment.MarshalJSON(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentUnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  env.Environment
+	}{
+		{
+			name:  "Empty",
+			input: `{}`,
+			want:  env.Environment{},
+		}, {
+			name:  "Entries",
+			input: `{"HOST":"localhost","PORT":"8080"}`,
+			want:  env.Environment{"HOST": "localhost", "PORT": "8080"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := env.Environment{"STALE": "value"}
+			if err := json.Unmarshal([]byte(tc.input), &got); err != nil {
+				t.Fatalf("Environment.UnmarshalJSON(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Environment.UnmarshalJSON(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentUnmarshalJSON_NonStringValue_ReturnsError(t *testing.T) {
+	var got env.Environment
+
+	err := json.Unmarshal([]byte(`{"PORT":8080}`), &got)
+
+	if err == nil {
+		t.Errorf("Environment.UnmarshalJSON(): got nil error, want error")
+	}
+}
+
+func TestEnvironmentJSON_RoundTrip(t *testing.T) {
+	want := env.Environment{
+		"HOST":      "localhost",
+		"MULTILINE": "line1\nline2",
+		"EMPTY":     "",
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Environment.MarshalJSON(): unexpected error: %v", err)
+	}
+	var got env.Environment
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Environment.UnmarshalJSON(): unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("Environment JSON round trip: got '%v', want '%v'", got, want)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 var (
@@ -31,6 +33,28 @@ var (
 	// environment variable. When an error is determined to be this type, it can
 	// be converted into a [ParseError].
 	ErrParse = fmt.Errorf("%w: parse error", errEnv)
+
+	// ErrExclusiveGroup is an error that occurs when a mutually-exclusive group of
+	// fields has more than one member set, or has no members set when the group
+	// is required. When an error is determined to be this type, it can be
+	// converted into an [ExclusiveGroupError].
+	ErrExclusiveGroup = fmt.Errorf("%w: exclusive group error", errEnv)
+
+	// ErrValidation is an error that occurs when a value was parsed successfully,
+	// but violates a constraint placed on it. When an error is determined to be
+	// this type, it can be converted into a [ValidationError].
+	ErrValidation = fmt.Errorf("%w: validation error", errEnv)
+
+	// ErrUnknownKey is an error that occurs when an environment contains keys
+	// that were not consumed by any struct field while unmarshaling with the
+	// [DisallowUnknownKeys] option. When an error is determined to be this type,
+	// it can be converted into an [UnknownKeyError].
+	ErrUnknownKey = fmt.Errorf("%w: unknown key", errEnv)
+
+	// ErrSyntax is an error that occurs when dotenv text read with
+	// [UnmarshalReader] is malformed. When an error is determined to be this
+	// type, it can be converted into a [SyntaxError].
+	ErrSyntax = fmt.Errorf("%w: syntax error", errEnv)
 )
 
 // InvalidTagOptionError is an error that occurs when an invalid tag option is
@@ -94,13 +118,16 @@ type ParseError struct {
 	// Key is the environment variable key that caused the error.
 	Key string
 
-	// Value is the value that caused the error.
+	// Value is the value that caused the error. This is masked if the field is
+	// tagged with the `secret` option.
 	Value string
 
 	// Type is the type that caused the error.
 	Type reflect.Type
 
-	// Err is the underlying error that was triggered during parsing.
+	// Err is the underlying error that was triggered during parsing. Unless
+	// the [VerboseErrors] option is used, any quoted occurrence of the value in
+	// its message is masked, so that values do not leak into logs.
 	Err error
 }
 
@@ -114,14 +141,143 @@ func (e *ParseError) Unwrap() []error {
 
 var _ error = (*ParseError)(nil)
 
+// IndexError is an error that occurs when a single element of a slice value
+// cannot be decoded. It is always wrapped in the [ParseError] of the slice.
+type IndexError struct {
+	// Key is the environment variable key of the slice.
+	Key string
+
+	// Index is the zero-based index of the element that caused the error.
+	Index int
+
+	// Value is the raw value of the element that caused the error. This is
+	// masked if the field is tagged with the `secret` option.
+	Value string
+
+	// Err is the error that occurred while decoding the element.
+	Err error
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("element %d of %s: %v", e.Index, e.Key, e.Err)
+}
+
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+var _ error = (*IndexError)(nil)
+
+// redactedError wraps an error that may contain a value read from the
+// environment, and masks that value in its message. Only quoted occurrences of
+// the value are masked, which is how the standard library's parse errors
+// present them. If secret is set, the message is replaced entirely, since any
+// part of it may reveal something about the value.
+type redactedError struct {
+	err    error
+	value  string
+	secret bool
+}
+
+func (e *redactedError) Error() string {
+	if e.secret {
+		return "invalid secret value"
+	}
+	message := e.err.Error()
+	if e.value == "" {
+		return message
+	}
+	return strings.ReplaceAll(message, strconv.Quote(e.value), strconv.Quote(string(redacted)))
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// ValidationError is an error that occurs when a value was parsed
+// successfully, but violates a constraint placed on it.
+type ValidationError struct {
+	// Key is the environment variable key that caused the error.
+	Key string
+
+	// Value is the value that caused the error. This is masked if the field is
+	// tagged with the `secret` option.
+	Value string
+
+	// Type is the type that caused the error.
+	Type reflect.Type
+
+	// Err describes the constraint that was violated.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("env: invalid %s: %v", e.Type, e.Err)
+	}
+	return fmt.Sprintf("env: invalid value for env variable '%s': %v", e.Key, e.Err)
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return []error{e.Err, ErrValidation}
+}
+
+var _ error = (*ValidationError)(nil)
+
+// UnknownKeyError is an error that occurs when an environment contains keys
+// that were not consumed by any struct field while unmarshaling with the
+// [DisallowUnknownKeys] option.
+type UnknownKeyError struct {
+	// Keys are the environment variable keys that were not consumed, sorted by
+	// key.
+	Keys []string
+}
+
+func (e *UnknownKeyError) Error() string {
+	return fmt.Sprintf("env: unknown env values '%s'", strings.Join(e.Keys, "', '"))
+}
+
+func (e *UnknownKeyError) Unwrap() error {
+	return ErrUnknownKey
+}
+
+var _ error = (*UnknownKeyError)(nil)
+
+// SyntaxError is an error that occurs when dotenv text is malformed.
+type SyntaxError struct {
+	// Line is the one-based line number that the error occurred on.
+	Line int
+
+	// Msg describes the error.
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("env: syntax error on line %d: %s", e.Line, e.Msg)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrSyntax
+}
+
+var _ error = (*SyntaxError)(nil)
+
 // RequirementError is an error that occurs when a required environment variable
 // is missing.
 type RequirementError struct {
 	Key  string
 	Type reflect.Type
+
+	// Condition is the key of the environment variable that caused Key to be
+	// required through the `requiredIf` tag option. This is empty if the key
+	// was unconditionally required.
+	Condition string
 }
 
 func (e *RequirementError) Error() string {
+	if e.Condition != "" {
+		return fmt.Sprintf("env: missing env value '%s', required when '%s' is set", e.Key, e.Condition)
+	}
 	return fmt.Sprintf("env: missing required env value '%s'", e.Key)
 }
 
@@ -130,3 +286,73 @@ func (e *RequirementError) Unwrap() error {
 }
 
 var _ error = (*RequirementError)(nil)
+
+// ExclusiveGroupError is an error that occurs when a mutually-exclusive group
+// of fields has more than one member set, or has no members set when the group
+// is required.
+type ExclusiveGroupError struct {
+	// Group is the name of the group that caused the error.
+	Group string
+
+	// Keys are the environment variable keys in the group that were set. This is
+	// empty if the group was required but none of its members were set.
+	Keys []string
+}
+
+func (e *ExclusiveGroupError) Error() string {
+	if len(e.Keys) == 0 {
+		return fmt.Sprintf("env: one env value in group '%s' must be set", e.Group)
+	}
+	return fmt.Sprintf("env: only one env value in group '%s' may be set, got '%s'", e.Group, strings.Join(e.Keys, "', '"))
+}
+
+func (e *ExclusiveGroupError) Unwrap() error {
+	return ErrExclusiveGroup
+}
+
+var _ error = (*ExclusiveGroupError)(nil)
+
+// MultiError is an error that combines several errors, such as every error
+// that occurred while decoding a struct. Each of the errors may be inspected
+// with [errors.Is] and [errors.As], regardless of whether they wrap a single
+// error or several.
+type MultiError struct {
+	errs []error
+}
+
+// NewMultiError returns a [MultiError] that combines the non-nil errors, in
+// order. Like [errors.Join], this returns nil if every error is nil.
+func NewMultiError(errs ...error) error {
+	result := &MultiError{}
+	for _, err := range errs {
+		if err != nil {
+			result.errs = append(result.errs, err)
+		}
+	}
+	if len(result.errs) == 0 {
+		return nil
+	}
+	return result
+}
+
+// Errors returns the errors that this error combines, in order.
+func (e *MultiError) Errors() []error {
+	return append([]error(nil), e.errs...)
+}
+
+func (e *MultiError) Error() string {
+	if len(e.errs) == 1 {
+		return e.errs[0].Error()
+	}
+	messages := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("env: %d errors occurred:\n\t%s", len(e.errs), strings.Join(messages, "\n\t"))
+}
+
+func (e *MultiError) Unwrap() []error {
+	return e.errs
+}
+
+var _ error = (*MultiError)(nil)
@@ -0,0 +1,109 @@
+package env_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"rodusek.dev/pkg/env"
+)
+
+func TestNewMultiError_NoErrors_ReturnsNil(t *testing.T) {
+	testCases := []struct {
+		name string
+		errs []error
+	}{
+		{
+			name: "No errors",
+			errs: nil,
+		}, {
+			name: "Only nil errors",
+			errs: []error{nil, nil},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := env.NewMultiError(tc.errs...)
+
+			if err != nil {
+				t.Errorf("NewMultiError(%s): got '%v', want nil", tc.name, err)
+			}
+		})
+	}
+}
+
+func TestMultiErrorErrors(t *testing.T) {
+	first := &env.RequirementError{Key: "HOST"}
+	second := &env.ParseError{Key: "PORT", Type: reflect.TypeFor[int](), Err: errors.New("bad")}
+
+	err := env.NewMultiError(first, nil, second)
+
+	var multi *env.MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("NewMultiError(): got err '%v', want MultiError", err)
+	}
+	if got, want := multi.Errors(), []error{first, second}; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("MultiError.Errors(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestMultiErrorError(t *testing.T) {
+	testCases := []struct {
+		name string
+		errs []error
+		want string
+	}{
+		{
+			name: "Single error",
+			errs: []error{&env.RequirementError{Key: "HOST"}},
+			want: "env: missing required env value 'HOST'",
+		}, {
+			name: "Several errors",
+			errs: []error{
+				&env.RequirementError{Key: "HOST"},
+				&env.RequirementError{Key: "PORT"},
+			},
+			want: "env: 2 errors occurred:\n" +
+				"\tenv: missing required env value 'HOST'\n" +
+				"\tenv: missing required env value 'PORT'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := env.NewMultiError(tc.errs...)
+
+			if got, want := err.Error(), tc.want; got != want {
+				t.Errorf("MultiError.Error(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestMultiError_IsAndAs_TraverseEachError(t *testing.T) {
+	cause := errors.New("bad")
+	err := env.NewMultiError(
+		&env.RequirementError{Key: "HOST"},
+		&env.ParseError{Key: "PORT", Type: reflect.TypeFor[int](), Err: cause},
+	)
+
+	for _, want := range []error{env.ErrRequirement, env.ErrParse, cause} {
+		if !errors.Is(err, want) {
+			t.Errorf("errors.Is(MultiError): got false, want true for '%v'", want)
+		}
+	}
+	if got, want := err, env.ErrValidation; errors.Is(got, want) {
+		t.Errorf("errors.Is(MultiError): got true, want false for '%v'", want)
+	}
+
+	var parseErr *env.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("errors.As(MultiError): got false, want ParseError")
+	}
+	if got, want := parseErr.Key, "PORT"; got != want {
+		t.Errorf("errors.As(MultiError): got key '%v', want '%v'", got, want)
+	}
+}
@@ -0,0 +1,166 @@
+package env
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Hash computes a stable content hash of the environment-backed fields of the
+// given struct, such as one previously populated by [Unmarshal].
+//
+// The hash is computed from the environment variable key and value of every
+// exported field, other than those tagged `env:"-"`, and is independent of the
+// order the fields are declared in. Untagged embedded structs are flattened,
+// as they are by [Unmarshal], and the fields of nested struct values are
+// likewise hashed by name rather than by position. Two structs that would
+// read the same keys and hold the same values will always produce the same
+// hash, which makes this suitable for detecting whether a configuration has
+// changed between reloads.
+//
+// The input must be a struct or a pointer to a struct; otherwise an
+// [InvalidTypeError] is returned.
+func Hash(in any) (string, error) {
+	if in == nil {
+		return "", fmt.Errorf("env: cannot hash nil value")
+	}
+
+	rv := reflect.ValueOf(in)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "", fmt.Errorf("env: cannot hash nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	rt := rv.Type()
+	if rt.Kind() != reflect.Struct {
+		return "", &InvalidTypeError{
+			Type: rt,
+		}
+	}
+
+	entries := hashFields(rv, nil)
+	sort.Strings(entries)
+
+	hash := sha256.New()
+	for _, entry := range entries {
+		// Quoting keeps entries unambiguous even if values contain newlines.
+		io.WriteString(hash, strconv.Quote(entry))
+		io.WriteString(hash, "\n")
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// hashFields appends a `KEY=value` entry for every environment-backed field of
+// the struct to entries. Untagged embedded structs are flattened, as they are
+// by [Unmarshal], so that their fields are hashed as if they were declared in
+// the embedding struct; nil embedded pointers contribute no entries.
+func hashFields(rv reflect.Value, entries []string, opts ...UnmarshalOption) []string {
+	base := newTagOptions(opts...)
+	fields := cachedStructFields(rv.Type())
+	for i := range fields {
+		field := &fields[i].field
+		fv := rv.FieldByIndex(field.Index)
+		if isEmbeddedStruct(field) {
+			embeddedOpts, ok := base.enterEmbedded(rv.Type(), field, opts)
+			if !ok {
+				continue
+			}
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			entries = hashFields(fv, entries, embeddedOpts...)
+			continue
+		}
+		entries = append(entries, KeyFor(*field)+"="+hashValue(fv))
+	}
+	return entries
+}
+
+// hashValue formats the value into a deterministic string representation that
+// does not depend on pointer addresses, map iteration order, or the order that
+// struct fields are declared in.
+//
+// Scalar values are always quoted, so that they can never be confused with the
+// bare "nil" used for nil values, nor with the brackets of composite values.
+func hashValue(rv reflect.Value) string {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return "nil"
+		}
+		if marshaler, ok := rv.Interface().(encoding.TextMarshaler); ok {
+			return hashText(marshaler)
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.CanInterface() {
+		if marshaler, ok := rv.Interface().(encoding.TextMarshaler); ok {
+			return hashText(marshaler)
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return "nil"
+		}
+		elems := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			elems = append(elems, hashValue(rv.Index(i)))
+		}
+		return "[" + strings.Join(elems, ",") + "]"
+	case reflect.Map:
+		if rv.IsNil() {
+			return "nil"
+		}
+		elems := make([]string, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			elems = append(elems, hashValue(iter.Key())+":"+hashValue(iter.Value()))
+		}
+		sort.Strings(elems)
+		return "{" + strings.Join(elems, ",") + "}"
+	case reflect.Struct:
+		// Fields are hashed by name rather than position. Unexported fields are
+		// included under their Go name, since they may still hold the state of
+		// types that are not environment-backed.
+		rt := rv.Type()
+		elems := make([]string, 0, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			field := rt.Field(i)
+			if isIgnored(&field) {
+				continue
+			}
+			name := field.Name
+			if field.IsExported() {
+				name = KeyFor(field)
+			}
+			elems = append(elems, strconv.Quote(name)+":"+hashValue(rv.Field(i)))
+		}
+		sort.Strings(elems)
+		return "{" + strings.Join(elems, ",") + "}"
+	default:
+		if !rv.CanInterface() {
+			return strconv.Quote(fmt.Sprintf("%v", rv))
+		}
+		return strconv.Quote(fmt.Sprintf("%v", rv.Interface()))
+	}
+}
+
+func hashText(marshaler encoding.TextMarshaler) string {
+	text, err := marshaler.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("error(%s)", strconv.Quote(err.Error()))
+	}
+	return strconv.Quote(string(text))
+}
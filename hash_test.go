@@ -0,0 +1,193 @@
+package env_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"rodusek.dev/pkg/env"
+)
+
+func TestHash_IdenticalConfigs_HashEqually(t *testing.T) {
+	newConfig := func() *OptionalEnv {
+		s := "Hello World"
+		return &OptionalEnv{
+			PtrString:     &s,
+			String:        "Hello",
+			Int:           42,
+			Duration:      5 * time.Second,
+			Time:          time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			StringSlice:   []string{"Hello", "World"},
+			DurationSlice: []time.Duration{time.Second, time.Minute},
+		}
+	}
+
+	got, err := env.Hash(newConfig())
+	if err != nil {
+		t.Fatalf("Hash(): unexpected error: %v", err)
+	}
+	want, err := env.Hash(newConfig())
+	if err != nil {
+		t.Fatalf("Hash(): unexpected error: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("Hash(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestHash_ChangedField_HashesDiffer(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mutate func(*OptionalEnv)
+	}{
+		{
+			name:   "String",
+			mutate: func(e *OptionalEnv) { e.String = "Goodbye" },
+		}, {
+			name:   "Pointer String",
+			mutate: func(e *OptionalEnv) { s := "Goodbye"; e.PtrString = &s },
+		}, {
+			name:   "Int",
+			mutate: func(e *OptionalEnv) { e.Int = 43 },
+		}, {
+			name:   "Time",
+			mutate: func(e *OptionalEnv) { e.Time = e.Time.Add(time.Second) },
+		}, {
+			name:   "Slice",
+			mutate: func(e *OptionalEnv) { e.StringSlice = []string{"Hello"} },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := "Hello World"
+			base := OptionalEnv{
+				PtrString:   &s,
+				String:      "Hello",
+				Int:         42,
+				Time:        time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				StringSlice: []string{"Hello", "World"},
+			}
+			changed := base
+			tc.mutate(&changed)
+
+			got, err := env.Hash(&changed)
+			if err != nil {
+				t.Fatalf("Hash(%s): unexpected error: %v", tc.name, err)
+			}
+			original, err := env.Hash(&base)
+			if err != nil {
+				t.Fatalf("Hash(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got == original {
+				t.Errorf("Hash(%s): got '%v', want a different hash", tc.name, got)
+			}
+		})
+	}
+}
+
+func TestHash_FieldOrder_DoesNotAffectHash(t *testing.T) {
+	type First struct {
+		Name string `env:"NAME"`
+		Port int    `env:"PORT"`
+	}
+	type Second struct {
+		Port int    `env:"PORT"`
+		Name string `env:"NAME"`
+	}
+
+	got, err := env.Hash(First{Name: "example", Port: 8080})
+	if err != nil {
+		t.Fatalf("Hash(): unexpected error: %v", err)
+	}
+	want, err := env.Hash(Second{Port: 8080, Name: "example"})
+	if err != nil {
+		t.Fatalf("Hash(): unexpected error: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("Hash(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestHash_IgnoredField_DoesNotAffectHash(t *testing.T) {
+	type IgnoredEnv struct {
+		Name  string `env:"NAME"`
+		Cache string `env:"-"`
+	}
+
+	got, err := env.Hash(IgnoredEnv{Name: "example", Cache: "first"})
+	if err != nil {
+		t.Fatalf("Hash(): unexpected error: %v", err)
+	}
+	want, err := env.Hash(IgnoredEnv{Name: "example", Cache: "second"})
+	if err != nil {
+		t.Fatalf("Hash(): unexpected error: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("Hash(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestHash_NotAStruct_ReturnsError(t *testing.T) {
+	_, err := env.Hash(42)
+
+	if !errors.Is(err, env.ErrInvalidType) {
+		t.Errorf("Hash(): got err '%v', want '%v'", err, env.ErrInvalidType)
+	}
+}
+
+func TestHash_EmbeddedFieldOrder_DoesNotAffectHash(t *testing.T) {
+	type FirstCommon struct {
+		Name string `env:"NAME"`
+		Port int    `env:"PORT"`
+	}
+	type SecondCommon struct {
+		Port int    `env:"PORT"`
+		Name string `env:"NAME"`
+	}
+	type First struct {
+		FirstCommon
+		Debug bool `env:"DEBUG"`
+	}
+	type Second struct {
+		Debug bool `env:"DEBUG"`
+		SecondCommon
+	}
+
+	got, err := env.Hash(First{FirstCommon: FirstCommon{Name: "example", Port: 8080}, Debug: true})
+	if err != nil {
+		t.Fatalf("Hash(): unexpected error: %v", err)
+	}
+	want, err := env.Hash(Second{SecondCommon: SecondCommon{Port: 8080, Name: "example"}, Debug: true})
+	if err != nil {
+		t.Fatalf("Hash(): unexpected error: %v", err)
+	}
+
+	if got != want {
+		t.Errorf("Hash(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestHash_NilPointer_DiffersFromValue(t *testing.T) {
+	type PointerEnv struct {
+		Name *string `env:"NAME"`
+	}
+	sentinel := "<nil>"
+
+	got, err := env.Hash(PointerEnv{})
+	if err != nil {
+		t.Fatalf("Hash(): unexpected error: %v", err)
+	}
+	other, err := env.Hash(PointerEnv{Name: &sentinel})
+	if err != nil {
+		t.Fatalf("Hash(): unexpected error: %v", err)
+	}
+
+	if got == other {
+		t.Errorf("Hash(): got '%v', want a different hash", got)
+	}
+}
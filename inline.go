@@ -0,0 +1,170 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"rodusek.dev/pkg/env/internal/dotenv"
+)
+
+// inlineParsers are the formats supported by the `inline` tag option, which
+// parse the value of a single key into the entries of a nested struct.
+var inlineParsers = map[string]func(value string) (map[string]string, error){
+	"dotenv": dotenv.ParseInline,
+	"json":   parseInlineJSON,
+}
+
+// inlineFormatters format the entries of a nested struct into the value of a
+// single key for each of the formats in inlineParsers.
+var inlineFormatters = map[string]func(entries map[string]string) (string, error){
+	"dotenv": formatInlineDotenv,
+	"json":   formatInlineJSON,
+}
+
+// isInlineStruct returns true if the type is a nested struct, or a pointer to
+// one, that may be decoded with the `inline` tag option.
+func isInlineStruct(rt reflect.Type) bool {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return isNestedStruct(rt)
+}
+
+// decodeInline decodes a nested struct from the entries parsed from the value
+// of a single key. Nil pointers are only allocated once decoding succeeds.
+func decodeInline(tag *tagOptions, rt reflect.Type, rv reflect.Value, opts ...UnmarshalOption) error {
+	if !rv.CanSet() {
+		return fmt.Errorf("env: cannot set field for '%s'", tag.key)
+	}
+	if tag.required && tag.missing() {
+		return &RequirementError{
+			Key:       tag.missingKey(),
+			Type:      rt,
+			Condition: tag.requiredIf,
+		}
+	}
+	if !tag.set {
+		return nil
+	}
+
+	entries, err := inlineParsers[tag.inline](tag.value)
+	if err != nil {
+		return tag.parseError(rt, err)
+	}
+	lookup := func(key string) (string, bool) {
+		value, ok := entries[key]
+		return value, ok
+	}
+
+	structType := rt
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	target := reflect.New(structType).Elem()
+	if existing := derefExisting(rv); existing.IsValid() {
+		target.Set(existing)
+	}
+	// The keys of the entries are not environment variables, so they are
+	// neither traced nor reported; the key of the field itself already is.
+	inlineOpts := append(opts[:len(opts):len(opts)], withPrefix(""), apply(func(tag *tagOptions) {
+		tag.tracer = nil
+		tag.report = nil
+	}))
+	if err := decodeStruct(lookup, target, structType, inlineOpts...); err != nil {
+		return err
+	}
+
+	rv, _ = deref(rv, rt)
+	rv.Set(target)
+	return nil
+}
+
+// encodeInline encodes a nested struct into the value of a single key in the
+// format of its `inline` option. This returns false if the struct is a nil
+// pointer, which should be omitted rather than encoded.
+func encodeInline(tag *tagOptions, rv reflect.Value, opts ...UnmarshalOption) (string, bool, error) {
+	rv = derefExisting(rv)
+	if !rv.IsValid() {
+		return "", false, nil
+	}
+	entries := Environment{}
+	// The keys of the entries are not environment variables, so they are
+	// neither traced nor reported; the key of the field itself already is.
+	inlineOpts := append(opts[:len(opts):len(opts)], withPrefix(""), apply(func(tag *tagOptions) {
+		tag.tracer = nil
+		tag.report = nil
+	}))
+	if err := encodeStruct(entries, rv, inlineOpts...); err != nil {
+		return "", false, err
+	}
+	value, err := inlineFormatters[tag.inline](entries.ToMap())
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// derefExisting returns the struct that the value points to, or an invalid
+// value if any of the pointers are nil. This preserves any defaults held by an
+// existing struct.
+func derefExisting(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+// parseInlineJSON parses a JSON object into its entries. String values are
+// used verbatim, and any other values are used in their JSON form.
+func parseInlineJSON(value string) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return nil, err
+	}
+	entries := make(map[string]string, len(raw))
+	for key, message := range raw {
+		var str string
+		if bytes.HasPrefix(message, []byte(`"`)) {
+			if err := json.Unmarshal(message, &str); err != nil {
+				return nil, err
+			}
+		} else {
+			str = string(message)
+		}
+		entries[key] = str
+	}
+	return entries, nil
+}
+
+// formatInlineDotenv formats the entries as whitespace-separated assignments,
+// sorted by key, quoting any values that would not be parsed verbatim.
+func formatInlineDotenv(entries map[string]string) (string, error) {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	assignments := make([]string, 0, len(keys))
+	for _, key := range keys {
+		assignments = append(assignments, key+"="+dotenv.Quote(entries[key]))
+	}
+	return strings.Join(assignments, " "), nil
+}
+
+// formatInlineJSON formats the entries as a JSON object of string values,
+// which [parseInlineJSON] uses verbatim.
+func formatInlineJSON(entries map[string]string) (string, error) {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,236 @@
+package dotenv_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"rodusek.dev/pkg/env/internal/dotenv"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{
+			name:  "Empty input",
+			input: "",
+			want:  map[string]string{},
+		}, {
+			name: "Unquoted values",
+			input: `
+				HOST=localhost
+				PORT = 8080
+				EMPTY=
+			`,
+			want: map[string]string{"HOST": "localhost", "PORT": "8080", "EMPTY": ""},
+		}, {
+			name: "Comments",
+			input: `
+				# A comment
+				HOST=localhost # trailing comment
+				COLOR=#fff
+			`,
+			want: map[string]string{"HOST": "localhost", "COLOR": "#fff"},
+		}, {
+			name:  "Export prefix",
+			input: "export HOST=localhost",
+			want:  map[string]string{"HOST": "localhost"},
+		}, {
+			name:  "Single-quoted value",
+			input: `GREETING='Hello # $World\n'`,
+			want:  map[string]string{"GREETING": `Hello # $World\n`},
+		}, {
+			name:  "Double-quoted value",
+			input: `GREETING="Hello\t\"World\"\n\$HOME\\"`,
+			want:  map[string]string{"GREETING": "Hello\t\"World\"\n$HOME\\"},
+		}, {
+			name:  "Multiline double-quoted value",
+			input: "KEY=\"line1\nline2\"\nNEXT=value",
+			want:  map[string]string{"KEY": "line1\nline2", "NEXT": "value"},
+		}, {
+			name:  "Escaped newlines",
+			input: `KEY="line1\nline2"`,
+			want:  map[string]string{"KEY": "line1\nline2"},
+		}, {
+			name:  "Triple double-quoted block",
+			input: "KEY=\"\"\"\nline1\n  \"line2\" $HOME \\n\n\"\"\"\nNEXT=value",
+			want:  map[string]string{"KEY": "line1\n  \"line2\" $HOME \\n", "NEXT": "value"},
+		}, {
+			name:  "Triple single-quoted block",
+			input: "KEY='''a\nb''' # comment",
+			want:  map[string]string{"KEY": "a\nb"},
+		}, {
+			name:  "Triple-quoted block with CRLF",
+			input: "KEY=\"\"\"\r\na\r\nb\r\n\"\"\"\r\n",
+			want:  map[string]string{"KEY": "a\r\nb"},
+		}, {
+			name:  "Empty triple-quoted block",
+			input: `KEY=""""""`,
+			want:  map[string]string{"KEY": ""},
+		}, {
+			name:  "CRLF line endings",
+			input: "HOST=localhost\r\nPORT=\"8080\"\r\n",
+			want:  map[string]string{"HOST": "localhost", "PORT": "8080"},
+		}, {
+			name:  "Last assignment wins",
+			input: "HOST=a\nHOST=b",
+			want:  map[string]string{"HOST": "b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := dotenv.Parse(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("Parse(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Parse(%s): mismatch (-want +got):\n%s", tc.name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestParse_InvalidInput_ReturnsSyntaxError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		wantLine int
+	}{
+		{name: "Missing separator", input: "HOST=a\nPORT", wantLine: 2},
+		{name: "Missing key", input: "=value", wantLine: 1},
+		{name: "Invalid key", input: "HO ST=value", wantLine: 1},
+		{name: "Unterminated double quote", input: "A=1\nKEY=\"value\n\n", wantLine: 2},
+		{name: "Unterminated single quote", input: "KEY='value", wantLine: 1},
+		{name: "Embedded NUL", input: "A=1\nB=x\x00y", wantLine: 2},
+		{name: "Embedded NUL in quotes", input: "A=\"\n\x00\"", wantLine: 2},
+		{name: "Unterminated triple quote", input: "A=1\nKEY=\"\"\"\nvalue\n\"\"", wantLine: 2},
+		{name: "Error after triple quote", input: "KEY=\"\"\"\na\n\"\"\" extra", wantLine: 3},
+		{name: "Text after quoted value", input: `KEY="value" extra`, wantLine: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := dotenv.Parse(strings.NewReader(tc.input))
+
+			var syntaxErr *dotenv.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%s): got err '%v', want SyntaxError", tc.name, err)
+			}
+			if got, want := syntaxErr.Line, tc.wantLine; got != want {
+				t.Errorf("Parse(%s): got line '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestParseInline(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "Whitespace-separated values",
+			input: "host=localhost port=5432",
+			want:  map[string]string{"host": "localhost", "port": "5432"},
+		}, {
+			name:  "Quoted values",
+			input: `user='admin' password="p a\"ss"`,
+			want:  map[string]string{"user": "admin", "password": `p a"ss`},
+		}, {
+			name:  "Empty value",
+			input: "host= port=5432",
+			want:  map[string]string{"host": "", "port": "5432"},
+		}, {
+			name:    "Missing separator",
+			input:   "host",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := dotenv.ParseInline(tc.input)
+
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("ParseInline(%s): got err '%v', want error '%v'", tc.name, err, want)
+			}
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("ParseInline(%s): mismatch (-want +got):\n%s", tc.name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	seeds := []string{
+		"",
+		"KEY=value",
+		"export KEY=value # comment",
+		"# comment only\n\n",
+		"KEY=\"double \\\"quoted\\\" \\n \\$HOME\"",
+		"KEY='single # quoted'",
+		"KEY=\"multi\nline\"",
+		"KEY=\"\"\"\ntriple\n\"\"\"",
+		"KEY='''triple'''",
+		"A=1\r\nB=\"2\"\r\n",
+		"KEY=\"unterminated",
+		"KEY='unterminated",
+		"KEY=\"\"\"unterminated",
+		"KEY",
+		"=value",
+		"KEY=\"value\" trailing",
+		"KEY=a\x00b",
+		"KEY=" + strings.Repeat("x", 1<<16),
+		"KEY=\"\\",
+		"1KEY.with-chars=value",
+		"KEY = spaced value \t",
+		"KEY=value\\\nNEXT=continued",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		got, err := dotenv.Parse(strings.NewReader(input))
+		if err != nil {
+			var syntaxErr *dotenv.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q): got err '%v', want SyntaxError", input, err)
+			}
+			if lines := strings.Count(input, "\n") + 1; syntaxErr.Line < 1 || syntaxErr.Line > lines {
+				t.Fatalf("Parse(%q): got line %d, want line in [1, %d]", input, syntaxErr.Line, lines)
+			}
+			return
+		}
+
+		for key, value := range got {
+			if key == "" || strings.ContainsAny(key, "= \t\r\n\x00") {
+				t.Fatalf("Parse(%q): got invalid key %q", input, key)
+			}
+			if strings.ContainsRune(value, 0) {
+				t.Fatalf("Parse(%q): got value with NUL byte for key %q", input, key)
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := dotenv.Write(&buf, got); err != nil {
+			t.Fatalf("Write(%q): unexpected error: %v", input, err)
+		}
+		reparsed, err := dotenv.Parse(&buf)
+		if err != nil {
+			t.Fatalf("Parse(%q): written output failed to parse: %v", input, err)
+		}
+		if !cmp.Equal(reparsed, got) {
+			t.Fatalf("Parse(%q): round trip mismatch (-want +got):\n%s", input, cmp.Diff(got, reparsed))
+		}
+	})
+}
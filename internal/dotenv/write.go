@@ -0,0 +1,50 @@
+package dotenv
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Write writes the entries to the writer as dotenv text, with one assignment
+// per line in ascending order of their keys. Values are quoted with [Quote]
+// where necessary, so that the output is parsed back into the same entries by
+// [Parse].
+func Write(w io.Writer, entries map[string]string) error {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	bw := bufio.NewWriter(w)
+	for _, key := range keys {
+		bw.WriteString(key)
+		bw.WriteByte('=')
+		bw.WriteString(Quote(entries[key]))
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// Quote returns the value in a form that may be assigned in dotenv text. Values
+// that would be parsed verbatim are returned as-is, and any other values are
+// double-quoted, escaping newlines, tabs, quotes, backslashes, and '$'.
+func Quote(value string) string {
+	if !strings.ContainsAny(value, " \t\r\n\"'#\\$=") {
+		return value
+	}
+	return `"` + quoter.Replace(value) + `"`
+}
+
+// quoter escapes the characters of a double-quoted value that would otherwise
+// be interpreted by the parser.
+var quoter = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	`$`, `\$`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+)
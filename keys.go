@@ -0,0 +1,95 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// KeyFor returns the environment variable key that the given struct field is
+// read from by [Unmarshal].
+//
+// This is the name specified in the field's `env` tag, or the field name
+// converted to screaming snake case with [ScreamingSnake] if no tag is present.
+// This does not account for any [NameFunc] option. An empty string is returned
+// for fields tagged `env:"-"`, which are never read.
+func KeyFor(field reflect.StructField) string {
+	if isIgnored(&field) {
+		return ""
+	}
+	key, _ := parseTag(&field)
+	return key
+}
+
+// ScreamingSnake converts the given Go identifier to screaming snake case, which
+// is how the keys of untagged fields are derived from their names. Words are
+// separated before an uppercase letter that follows a lowercase letter or a
+// digit, and before the last uppercase letter of an acronym that is followed by
+// a lowercase letter. For example, `ProjectName` becomes `PROJECT_NAME`,
+// `HTTPServer` becomes `HTTP_SERVER`, and `UserID` becomes `USER_ID`.
+func ScreamingSnake(name string) string {
+	runes := []rune(name)
+
+	var builder strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				builder.WriteByte('_')
+			}
+		}
+		builder.WriteRune(r)
+	}
+	return strings.ToUpper(builder.String())
+}
+
+// Keys returns every environment variable key that [Unmarshal] would read
+// when decoding into the given struct, in field declaration order.
+//
+// The input may be a struct, a pointer to a struct, or the [reflect.Type] of
+// either. Unexported fields are ignored, and the keys of untagged embedded
+// structs are included in place of the embedded field. An [InvalidTypeError] is
+// returned if the input does not describe a struct.
+func Keys(structType any) ([]string, error) {
+	if structType == nil {
+		return nil, fmt.Errorf("env: cannot read keys of nil value")
+	}
+
+	rt, ok := structType.(reflect.Type)
+	if !ok {
+		rt = reflect.TypeOf(structType)
+	}
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return nil, &InvalidTypeError{
+			Type: rt,
+		}
+	}
+
+	return structKeys(rt), nil
+}
+
+// structKeys returns the keys of every field of the struct type, with any
+// embedded structs flattened in place.
+func structKeys(rt reflect.Type, opts ...UnmarshalOption) []string {
+	base := newTagOptions(opts...)
+	fields := cachedStructFields(rt)
+	keys := make([]string, 0, len(fields))
+	for i := range fields {
+		field := &fields[i].field
+		if isEmbeddedStruct(field) {
+			embeddedOpts, ok := base.enterEmbedded(rt, field, opts)
+			if !ok {
+				continue
+			}
+			keys = append(keys, structKeys(elemType(field.Type), embeddedOpts...)...)
+			continue
+		}
+		keys = append(keys, base.fieldKey(field, fields[i].tag))
+	}
+	return keys
+}
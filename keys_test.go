@@ -0,0 +1,172 @@
+package env_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"rodusek.dev/pkg/env"
+)
+
+func TestKeyFor(t *testing.T) {
+	type KeyEnv struct {
+		Tagged      string `env:"CUSTOM_KEY"`
+		WithOptions string `env:"OPTIONS_KEY,required,sep=;"`
+		ProjectName string
+		HTTPPort    int
+		Ignored     string `env:"-"`
+		Dash        string `env:"-,"`
+	}
+
+	testCases := []struct {
+		name  string
+		field string
+		want  string
+	}{
+		{
+			name:  "Explicit tag",
+			field: "Tagged",
+			want:  "CUSTOM_KEY",
+		}, {
+			name:  "Explicit tag with options",
+			field: "WithOptions",
+			want:  "OPTIONS_KEY",
+		}, {
+			name:  "Untagged field",
+			field: "ProjectName",
+			want:  "PROJECT_NAME",
+		}, {
+			name:  "Untagged field with acronym",
+			field: "HTTPPort",
+			want:  "HTTP_PORT",
+		}, {
+			name:  "Ignored field",
+			field: "Ignored",
+			want:  "",
+		}, {
+			name:  "Literal dash key",
+			field: "Dash",
+			want:  "-",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			field, ok := reflect.TypeFor[KeyEnv]().FieldByName(tc.field)
+			if !ok {
+				t.Fatalf("KeyFor(%s): no such field '%s'", tc.name, tc.field)
+			}
+
+			if got, want := env.KeyFor(field), tc.want; got != want {
+				t.Errorf("KeyFor(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	type KeyEnv struct {
+		ProjectName  string        `env:"PROJECT_NAME,required"`
+		Timeout      time.Duration `env:"TIMEOUT"`
+		Path         []string      `env:"PATH,sep=;"`
+		AnonymousInt int
+		unexported   string
+	}
+	want := []string{"PROJECT_NAME", "TIMEOUT", "PATH", "ANONYMOUS_INT"}
+
+	testCases := []struct {
+		name  string
+		input any
+	}{
+		{
+			name:  "Struct value",
+			input: KeyEnv{},
+		}, {
+			name:  "Struct pointer",
+			input: &KeyEnv{},
+		}, {
+			name:  "Nil struct pointer",
+			input: (*KeyEnv)(nil),
+		}, {
+			name:  "Struct type",
+			input: reflect.TypeFor[KeyEnv](),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := env.Keys(tc.input)
+			if err != nil {
+				t.Fatalf("Keys(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if !cmp.Equal(got, want) {
+				t.Errorf("Keys(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestKeys_NotAStruct_ReturnsError(t *testing.T) {
+	_, err := env.Keys(42)
+
+	if got, want := err, env.ErrInvalidType; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Keys(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestKeys_EmbeddedStruct_FlattensKeys(t *testing.T) {
+	type Embedded struct {
+		Region string `env:"REGION"`
+	}
+	type KeyEnv struct {
+		*Embedded
+		Name string `env:"NAME"`
+	}
+	want := []string{"REGION", "NAME"}
+
+	got, err := env.Keys(KeyEnv{})
+	if err != nil {
+		t.Fatalf("Keys(): unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("Keys(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestScreamingSnake(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Empty", input: "", want: ""},
+		{name: "Single word", input: "Name", want: "NAME"},
+		{name: "Lower camel case", input: "projectName", want: "PROJECT_NAME"},
+		{name: "Upper camel case", input: "ProjectName", want: "PROJECT_NAME"},
+		{name: "Leading acronym", input: "HTTPServer", want: "HTTP_SERVER"},
+		{name: "Trailing acronym", input: "UserID", want: "USER_ID"},
+		{name: "Middle acronym", input: "MyHTTPServer", want: "MY_HTTP_SERVER"},
+		{name: "Only acronym", input: "URL", want: "URL"},
+		{name: "Adjacent acronyms", input: "APIURL", want: "APIURL"},
+		{name: "Plural acronym", input: "IDs", want: "I_DS"},
+		{name: "Single letter words", input: "AValue", want: "A_VALUE"},
+		{name: "Trailing digits", input: "Port8080", want: "PORT8080"},
+		{name: "Word after digits", input: "Base64Encoding", want: "BASE64_ENCODING"},
+		{name: "Acronym after digits", input: "Token2FA", want: "TOKEN2_FA"},
+		{name: "Existing underscores", input: "Already_Snake", want: "ALREADY_SNAKE"},
+		{name: "Already screaming", input: "PROJECT_NAME", want: "PROJECT_NAME"},
+		{name: "Non-ASCII", input: "ÜberName", want: "ÜBER_NAME"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := env.ScreamingSnake(tc.input), tc.want; got != want {
+				t.Errorf("ScreamingSnake(%s): got '%v', want '%v'", tc.input, got, want)
+			}
+		})
+	}
+}
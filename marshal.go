@@ -0,0 +1,363 @@
+package env
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Marshal encodes the given config struct into an [Environment], using the
+// same keys and value formats that [Unmarshal] decodes them from, so that the
+// result unmarshals back into an equivalent struct.
+//
+// Every field that [Unmarshal] would read is emitted, including fields that
+// hold their zero value. Fields tagged with the `omitempty` option are omitted
+// if they are empty: that is, if they are nil pointers, empty slices, maps, or
+// strings, or otherwise equal to their zero value. Fields that are also tagged
+// `required` are always emitted, since omitting them would produce an
+// environment that fails to unmarshal.
+//
+// Nil pointers are omitted, so that they are left nil by [Unmarshal]. Untagged
+// embedded structs are flattened into the result, and nil embedded pointers are
+// omitted. Slices of structs are emitted with indexed keys, such
+// as `UPSTREAM_0_HOST`. Unlike [Template], the values of fields tagged with the
+// `secret` option are emitted as-is. Fields tagged with the `presence` option
+// are emitted with an empty value when true, and omitted when false. Fields
+// tagged with the `inline` option are encoded into a single value in their
+// declared format. Types that implement
+// [driver.Valuer], such as [sql.NullString], are encoded from their driver
+// value, and omitted if it is null.
+//
+// The input may be a struct or a non-nil pointer to a struct. An
+// [InvalidTypeError] is returned for any other type, or if a value cannot be
+// encoded.
+func Marshal(in any, opts ...MarshalOption) (Environment, error) {
+	if in == nil {
+		return nil, fmt.Errorf("env: cannot marshal nil value")
+	}
+
+	rv := reflect.ValueOf(in)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("env: cannot marshal nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, &InvalidTypeError{
+			Type: rv.Type(),
+		}
+	}
+
+	result := Environment{}
+	if err := encodeStruct(result, rv, unmarshalOptions(opts)...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// encodeStruct encodes the fields of the struct into the environment.
+func encodeStruct(out Environment, rv reflect.Value, opts ...UnmarshalOption) error {
+	base := newTagOptions(opts...)
+	fields := cachedStructFields(rv.Type())
+	for i := range fields {
+		field := &fields[i].field
+		fv := rv.FieldByIndex(field.Index)
+		if isEmbeddedStruct(field) {
+			embeddedOpts, ok := base.enterEmbedded(rv.Type(), field, opts)
+			if !ok {
+				continue
+			}
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if err := encodeStruct(out, fv, embeddedOpts...); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tag, err := applyFieldTag(base, field, fields[i].tag)
+		if err != nil {
+			return err
+		}
+		if tag.omitEmpty && !tag.required && isEmptyValue(fv) {
+			continue
+		}
+		if tag.presence {
+			// Presence flags are decoded from whether the key is set, so a
+			// false flag must be omitted rather than emitted as "false".
+			if isPresent(fv) {
+				out[tag.key] = ""
+			}
+			continue
+		}
+		if isStructSlice(field.Type) {
+			if err := encodeStructSlice(out, tag, fv, opts...); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if (fv.Kind() == reflect.Ptr && fv.IsNil()) || isNullValuer(fv) {
+			// Nil pointers and null values, such as an invalid sql.NullString,
+			// are omitted so that they decode back into a nil or null value.
+			continue
+		}
+		if tag.inline != "" {
+			value, ok, err := encodeInline(tag, fv, opts...)
+			if err != nil {
+				return err
+			}
+			if ok {
+				out[tag.key] = Value(value)
+			}
+			continue
+		}
+
+		value, err := encodeValue(tag, fv)
+		if err != nil {
+			return err
+		}
+		out[tag.key] = Value(value)
+	}
+	return nil
+}
+
+// encodeStructSlice encodes each element of a slice of structs into the
+// environment, using keys indexed by a numeric suffix of the tag key. Nil
+// elements are skipped.
+func encodeStructSlice(out Environment, tag *tagOptions, rv reflect.Value, opts ...UnmarshalOption) error {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i)
+		for elem.Kind() == reflect.Ptr && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Ptr {
+			continue
+		}
+		prefix := fmt.Sprintf("%s_%d_", tag.key, i)
+		elemOpts := append(opts[:len(opts):len(opts)], withPrefix(prefix))
+		if err := encodeStruct(out, elem, elemOpts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isPresent returns true if the presence flag is set, which is only the case
+// for a true bool behind any number of non-nil pointers.
+func isPresent(rv reflect.Value) bool {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	return rv.Bool()
+}
+
+// isEmptyValue returns true if the value is considered empty for the purposes
+// of the `omitempty` option.
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+// encodeValue formats the value into the string form that [Unmarshal] would
+// decode it from. Nil pointers are encoded as an empty string.
+func encodeValue(tag *tagOptions, rv reflect.Value) (string, error) {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "", nil
+		}
+		rv = rv.Elem()
+	}
+	rt := rv.Type()
+
+	// Copy the value so that pointer-receiver marshalers can be detected even
+	// when the value itself is not addressable.
+	addr := reflect.New(rt)
+	addr.Elem().Set(rv)
+	if marshaler, ok := addr.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		return string(text), nil
+	}
+
+	if rt == durationType {
+		return time.Duration(rv.Int()).String(), nil
+	}
+	if rt == fileModeType {
+		return fmt.Sprintf("%#04o", rv.Uint()), nil
+	}
+
+	switch rt.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), tag.formatBase()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), tag.formatBase()), nil
+	case reflect.Float32, reflect.Float64:
+		if tag.percent {
+			return formatPercent(rv.Float(), bitness(rt)), nil
+		}
+		return strconv.FormatFloat(rv.Float(), 'g', -1, bitness(rt)), nil
+	case reflect.Complex64, reflect.Complex128:
+		return strconv.FormatComplex(rv.Complex(), 'g', -1, bitness(rt)), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Slice, reflect.Array:
+		entries := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			entry, err := encodeValue(tag, rv.Index(i))
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, entry)
+		}
+		return tag.joinEntries(entries)
+	case reflect.Map:
+		entries := make([]string, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, err := encodeValue(tag, iter.Key())
+			if err != nil {
+				return "", err
+			}
+			value, err := encodeValue(tag, iter.Value())
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, key+tag.kvsep+value)
+		}
+		sort.Strings(entries)
+		return tag.joinEntries(entries)
+	default:
+		// Types such as sql.NullString are encoded from their driver value, which
+		// mirrors how they are scanned when decoding.
+		if valuer, ok := addr.Interface().(driver.Valuer); ok {
+			return encodeDriverValue(tag, valuer)
+		}
+		return "", &InvalidTypeError{
+			Key:  tag.key,
+			Type: rt,
+		}
+	}
+}
+
+// encodeDriverValue formats the driver value of the valuer. Null values are
+// encoded as an empty string.
+func encodeDriverValue(tag *tagOptions, valuer driver.Valuer) (string, error) {
+	value, err := valuer.Value()
+	if err != nil {
+		return "", err
+	}
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case []byte:
+		return string(value), nil
+	case time.Time:
+		return value.Format(time.RFC3339Nano), nil
+	default:
+		return encodeValue(tag, reflect.ValueOf(value))
+	}
+}
+
+// isNullValuer returns true if the value is a struct that implements
+// [driver.Valuer], such as sql.NullString, and holds a null value.
+func isNullValuer(rv reflect.Value) bool {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return false
+	}
+	addr := reflect.New(rv.Type())
+	addr.Elem().Set(rv)
+	valuer, ok := addr.Interface().(driver.Valuer)
+	if !ok {
+		return false
+	}
+	value, err := valuer.Value()
+	return err == nil && value == nil
+}
+
+// joinEntries joins the entries of a slice or map value with the separator, honoring
+// the `csv` and `escape` options so that [Unmarshal] splits them back apart.
+func (t *tagOptions) joinEntries(entries []string) (string, error) {
+	if t.csv {
+		return joinCSV(t.sep, entries)
+	}
+	if t.escape && t.sep != "" {
+		return joinEscaped(t.sep, entries), nil
+	}
+	return strings.Join(entries, t.sep), nil
+}
+
+// formatBase returns the base that integers are formatted in, which is the
+// base set with the `base` option, or 10 if the base is inferred.
+func (t *tagOptions) formatBase() int {
+	if t.base < 2 || t.base > 36 {
+		return 10
+	}
+	return t.base
+}
+
+// joinCSV joins the entries as a single CSV record that uses sep as its
+// delimiter, quoting any entries that contain it.
+func joinCSV(sep string, entries []string) (string, error) {
+	comma, size := utf8.DecodeRuneInString(sep)
+	if size == 0 || size != len(sep) {
+		return "", fmt.Errorf("env: csv separator %q must be a single character", sep)
+	}
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Comma = comma
+	if err := writer.Write(entries); err != nil {
+		return "", err
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// joinEscaped joins the entries with sep, escaping any backslashes and
+// separators within the entries so that they may be split again.
+func joinEscaped(sep string, entries []string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, sep, `\`+sep)
+	escaped := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		escaped = append(escaped, escaper.Replace(entry))
+	}
+	return strings.Join(escaped, sep)
+}
@@ -0,0 +1,500 @@
+package env_test
+
+import (
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"rodusek.dev/pkg/env"
+)
+
+func TestMarshal(t *testing.T) {
+	type Upstream struct {
+		Host string `env:"HOST"`
+	}
+	type MarshalEnv struct {
+		ProjectName string            `env:"PROJECT_NAME,required"`
+		Timeout     time.Duration     `env:"TIMEOUT"`
+		Path        []string          `env:"PATH,sep=;"`
+		Labels      map[string]string `env:"LABELS"`
+		Port        *int              `env:"PORT"`
+		Upstreams   []Upstream        `env:"UPSTREAM"`
+		Greeting    string
+		unexported  string
+	}
+
+	testCases := []struct {
+		name  string
+		input any
+		want  env.Environment
+	}{
+		{
+			name:  "Zero values",
+			input: MarshalEnv{},
+			want: env.Environment{
+				"PROJECT_NAME": "",
+				"TIMEOUT":      "0s",
+				"PATH":         "",
+				"LABELS":       "",
+				"GREETING":     "",
+			},
+		}, {
+			name: "Set values",
+			input: &MarshalEnv{
+				ProjectName: "example",
+				Timeout:     5 * time.Second,
+				Path:        []string{"/usr/bin", "/bin"},
+				Labels:      map[string]string{"b": "2", "a": "1"},
+				Port:        ptr(8080),
+				Upstreams:   []Upstream{{Host: "a"}, {Host: "b"}},
+				Greeting:    "Hello World",
+			},
+			want: env.Environment{
+				"PROJECT_NAME":    "example",
+				"TIMEOUT":         "5s",
+				"PATH":            "/usr/bin;/bin",
+				"LABELS":          "a=1,b=2",
+				"PORT":            "8080",
+				"UPSTREAM_0_HOST": "a",
+				"UPSTREAM_1_HOST": "b",
+				"GREETING":        "Hello World",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := env.Marshal(tc.input)
+			if err != nil {
+				t.Fatalf("Marshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Marshal(%s): mismatch (-want +got):\n%s", tc.name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestMarshal_OmitEmpty(t *testing.T) {
+	type OmitEnv struct {
+		Name     string         `env:"NAME,omitempty"`
+		Tags     []string       `env:"TAGS,omitempty"`
+		Port     *int           `env:"PORT,omitempty"`
+		Labels   map[string]int `env:"LABELS,omitempty"`
+		Required string         `env:"REQUIRED,required,omitempty"`
+		Always   string         `env:"ALWAYS"`
+	}
+
+	testCases := []struct {
+		name  string
+		input OmitEnv
+		want  env.Environment
+	}{
+		{
+			name:  "Empty values",
+			input: OmitEnv{Tags: []string{}, Labels: map[string]int{}},
+			want: env.Environment{
+				"REQUIRED": "",
+				"ALWAYS":   "",
+			},
+		}, {
+			name: "Non-empty values",
+			input: OmitEnv{
+				Name:     "example",
+				Tags:     []string{"a"},
+				Port:     ptr(0),
+				Required: "value",
+			},
+			want: env.Environment{
+				"NAME":     "example",
+				"TAGS":     "a",
+				"PORT":     "0",
+				"REQUIRED": "value",
+				"ALWAYS":   "",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := env.Marshal(tc.input)
+			if err != nil {
+				t.Fatalf("Marshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Marshal(%s): mismatch (-want +got):\n%s", tc.name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	type RoundTripEnv struct {
+		Name    string         `env:"NAME,required"`
+		Timeout time.Duration  `env:"TIMEOUT"`
+		Ports   []int          `env:"PORTS"`
+		Limits  map[string]int `env:"LIMITS"`
+	}
+	want := RoundTripEnv{
+		Name:    "example",
+		Timeout: time.Minute,
+		Ports:   []int{80, 443},
+		Limits:  map[string]int{"cpu": 2},
+	}
+
+	environment, err := env.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal(): unexpected error: %v", err)
+	}
+	var got RoundTripEnv
+	if err := env.SealedEnvironment(environment).Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): round trip mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestMarshal_Escape_RoundTrips(t *testing.T) {
+	type EscapeEnv struct {
+		Tags []string `env:"TAGS,escape"`
+	}
+	want := EscapeEnv{Tags: []string{"a,b", `C:\dir\`, "c"}}
+
+	environment, err := env.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal(): unexpected error: %v", err)
+	}
+	if got, want := environment["TAGS"], env.Value(`a\,b,C:\\dir\\,c`); got != want {
+		t.Errorf("Marshal(): got '%v', want '%v'", got, want)
+	}
+	var got EscapeEnv
+	if err := env.SealedEnvironment(environment).Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): round trip mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestMarshal_NotAStruct_ReturnsError(t *testing.T) {
+	_, err := env.Marshal(42)
+
+	if got, want := err, env.ErrInvalidType; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Marshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestMarshal_NameFunc(t *testing.T) {
+	type NameEnv struct {
+		ProjectName string
+		Tagged      string `env:"TAGGED_KEY"`
+	}
+
+	got, err := env.Marshal(NameEnv{ProjectName: "example", Tagged: "tagged"}, env.NameFunc(strings.ToLower))
+	if err != nil {
+		t.Fatalf("Marshal(): unexpected error: %v", err)
+	}
+
+	want := env.Environment{"projectname": "example", "TAGGED_KEY": "tagged"}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestMarshal_IgnoredField_IsOmitted(t *testing.T) {
+	type IgnoredEnv struct {
+		Name  string `env:"NAME"`
+		Cache string `env:"-"`
+	}
+
+	got, err := env.Marshal(IgnoredEnv{Name: "example", Cache: "value"})
+	if err != nil {
+		t.Fatalf("Marshal(): unexpected error: %v", err)
+	}
+
+	if want := (env.Environment{"NAME": "example"}); !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestMarshal_FileMode_RoundTrips(t *testing.T) {
+	type FileModeEnv struct {
+		Mode  os.FileMode `env:"MODE"`
+		Umask os.FileMode `env:"UMASK"`
+	}
+	want := FileModeEnv{Mode: 0o644, Umask: 0o022}
+
+	environment, err := env.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal(): unexpected error: %v", err)
+	}
+	if got, want := environment, (env.Environment{"MODE": "0644", "UMASK": "0022"}); !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): got '%v', want '%v'", got, want)
+	}
+	var got FileModeEnv
+	if err := env.SealedEnvironment(environment).Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): round trip mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestMarshal_NilPointers_RoundTrip(t *testing.T) {
+	type PointerEnv struct {
+		Name *string `env:"NAME"`
+		Port *int    `env:"PORT"`
+		Host *string `env:"HOST"`
+	}
+	want := PointerEnv{Host: ptr("localhost")}
+
+	environment, err := env.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal(): unexpected error: %v", err)
+	}
+	wantEnv := env.Environment{"HOST": "localhost"}
+	if got, want := environment, wantEnv; !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): got '%v', want '%v'", got, want)
+	}
+	var got PointerEnv
+	if err := env.SealedEnvironment(environment).Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): round trip mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestMarshal_MapSeparatorInValue_RoundTrips(t *testing.T) {
+	type MapEnv struct {
+		CSV    map[string]string `env:"CSV,csv"`
+		Escape map[string]string `env:"ESCAPE,escape"`
+	}
+	labels := map[string]string{"a": "1", "b": "2,3"}
+	want := MapEnv{CSV: labels, Escape: labels}
+
+	environment, err := env.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal(): unexpected error: %v", err)
+	}
+	var got MapEnv
+	if err := env.SealedEnvironment(environment).Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): round trip mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestMarshal_Presence_RoundTrips(t *testing.T) {
+	type PresenceEnv struct {
+		Debug   bool  `env:"DEBUG,presence"`
+		Verbose *bool `env:"VERBOSE,presence"`
+	}
+	enabled := true
+
+	testCases := []struct {
+		name    string
+		input   PresenceEnv
+		wantEnv env.Environment
+		want    PresenceEnv
+	}{
+		{
+			name:    "False flags are omitted",
+			input:   PresenceEnv{},
+			wantEnv: env.Environment{},
+			want:    PresenceEnv{Verbose: new(bool)},
+		}, {
+			name:    "True flags are emitted empty",
+			input:   PresenceEnv{Debug: true, Verbose: &enabled},
+			wantEnv: env.Environment{"DEBUG": "", "VERBOSE": ""},
+			want:    PresenceEnv{Debug: true, Verbose: &enabled},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			environment, err := env.Marshal(tc.input)
+			if err != nil {
+				t.Fatalf("Marshal(%s): unexpected error: %v", tc.name, err)
+			}
+			if got, want := environment, tc.wantEnv; !cmp.Equal(got, want) {
+				t.Errorf("Marshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+			var got PresenceEnv
+			if err := env.SealedEnvironment(environment).Unmarshal(&got); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Marshal(%s): round trip mismatch (-want +got):\n%s", tc.name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestMarshal_Base_RoundTrips(t *testing.T) {
+	type BaseEnv struct {
+		Color  int    `env:"COLOR,base=16"`
+		Mask   uint8  `env:"MASK,base=2"`
+		Offset int    `env:"OFFSET,base=16"`
+		Plain  uint16 `env:"PLAIN"`
+	}
+	want := BaseEnv{Color: 255, Mask: 5, Offset: -16, Plain: 8}
+
+	environment, err := env.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal(): unexpected error: %v", err)
+	}
+	wantEnv := env.Environment{"COLOR": "ff", "MASK": "101", "OFFSET": "-10", "PLAIN": "8"}
+	if got, want := environment, wantEnv; !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): got '%v', want '%v'", got, want)
+	}
+	var got BaseEnv
+	if err := env.SealedEnvironment(environment).Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): round trip mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+type marshalCommon struct {
+	Name string `env:"NAME"`
+}
+
+func TestMarshal_UnexportedEmbeddedStruct(t *testing.T) {
+	type EmbeddedEnv struct {
+		marshalCommon
+		Port int `env:"PORT"`
+	}
+
+	got, err := env.Marshal(EmbeddedEnv{marshalCommon: marshalCommon{Name: "example"}, Port: 80})
+	if err != nil {
+		t.Fatalf("Marshal(): unexpected error: %v", err)
+	}
+
+	if want := (env.Environment{"NAME": "example", "PORT": "80"}); !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestMarshal_Inline_RoundTrips(t *testing.T) {
+	type Database struct {
+		Host string `env:"host"`
+		Port int    `env:"port"`
+		Name string `env:"name"`
+	}
+	type InlineEnv struct {
+		DotEnv Database  `env:"DOTENV_DB,inline=dotenv"`
+		JSON   *Database `env:"JSON_DB,inline=json"`
+	}
+
+	testCases := []struct {
+		name    string
+		input   InlineEnv
+		wantEnv env.Environment
+	}{
+		{
+			name:  "Both formats",
+			input: InlineEnv{DotEnv: Database{Host: "localhost", Port: 5432, Name: "my db"}, JSON: &Database{Host: "db", Port: 3306}},
+			wantEnv: env.Environment{
+				"DOTENV_DB": `host=localhost name="my db" port=5432`,
+				"JSON_DB":   `{"host":"db","name":"","port":"3306"}`,
+			},
+		}, {
+			name:  "Nil pointer is omitted",
+			input: InlineEnv{DotEnv: Database{Host: "localhost"}},
+			wantEnv: env.Environment{
+				"DOTENV_DB": `host=localhost name= port=0`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			environment, err := env.Marshal(tc.input)
+			if err != nil {
+				t.Fatalf("Marshal(%s): unexpected error: %v", tc.name, err)
+			}
+			if got, want := environment, tc.wantEnv; !cmp.Equal(got, want) {
+				t.Errorf("Marshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+			var got InlineEnv
+			if err := env.SealedEnvironment(environment).Unmarshal(&got); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if want := tc.input; !cmp.Equal(got, want) {
+				t.Errorf("Marshal(%s): round trip mismatch (-want +got):\n%s", tc.name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestMarshal_SQLNullTypes_RoundTrip(t *testing.T) {
+	type SQLEnv struct {
+		Name    sql.NullString  `env:"NAME"`
+		Port    sql.NullInt64   `env:"PORT"`
+		Ratio   sql.NullFloat64 `env:"RATIO"`
+		Enabled sql.NullBool    `env:"ENABLED"`
+	}
+
+	testCases := []struct {
+		name    string
+		input   SQLEnv
+		wantEnv env.Environment
+	}{
+		{
+			name: "Valid values",
+			input: SQLEnv{
+				Name:    sql.NullString{String: "db", Valid: true},
+				Port:    sql.NullInt64{Int64: 5432, Valid: true},
+				Ratio:   sql.NullFloat64{Float64: 0.5, Valid: true},
+				Enabled: sql.NullBool{Bool: true, Valid: true},
+			},
+			wantEnv: env.Environment{"NAME": "db", "PORT": "5432", "RATIO": "0.5", "ENABLED": "true"},
+		}, {
+			name:    "Empty valid string",
+			input:   SQLEnv{Name: sql.NullString{Valid: true}},
+			wantEnv: env.Environment{"NAME": ""},
+		}, {
+			name:    "Null values are omitted",
+			input:   SQLEnv{},
+			wantEnv: env.Environment{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			environment, err := env.Marshal(tc.input)
+			if err != nil {
+				t.Fatalf("Marshal(%s): unexpected error: %v", tc.name, err)
+			}
+			if got, want := environment, tc.wantEnv; !cmp.Equal(got, want) {
+				t.Errorf("Marshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+
+			var got SQLEnv
+			if err := env.SealedEnvironment(environment).Unmarshal(&got); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+			if want := tc.input; !cmp.Equal(got, want) {
+				t.Errorf("Marshal(%s): round trip mismatch (-want +got):\n%s", tc.name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// MissingRequired returns the keys of every required field of the given struct
+// that is not set in the environment, in the order the fields are declared.
+// This is useful for reporting every missing variable at once, such as in a
+// `config check` command, rather than failing on the first as [Unmarshal]
+// does.
+//
+// Requirements are evaluated exactly as they are by [Unmarshal], including
+// `requiredIf` conditions, `nonempty` options, feature gates, and slices of
+// structs, and the same options are accepted. No individual member of a
+// mutually-exclusive group is required, so if a required group has no member
+// set, the keys of all of its members are reported after the other keys of the
+// struct, since setting any one of them satisfies it. Nothing is decoded, and
+// the input is never modified.
+//
+// The input may be a struct or a pointer to a struct. An [InvalidTypeError] is
+// returned for any other type, and an [InvalidTagOptionError] for any invalid
+// tag option.
+func MissingRequired(out any, opts ...UnmarshalOption) ([]string, error) {
+	if out == nil {
+		return nil, fmt.Errorf("env: cannot check nil value")
+	}
+
+	rt := reflect.TypeOf(out)
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return nil, &InvalidTypeError{
+			Type: rt,
+		}
+	}
+
+	tag := newTagOptions(opts...)
+	keys, err := missingRequired(tag.lookupOr(os.LookupEnv), rt, opts...)
+	if err := tag.lookupFailed(err); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// missingRequired returns the keys of every required field of the struct type
+// that is not set in the lookup.
+func missingRequired(lookup lookup, rt reflect.Type, opts ...UnmarshalOption) ([]string, error) {
+	base := newTagOptions(opts...)
+	var missing []string
+	var groups []*exclusiveGroup
+	members := map[string][]string{}
+	fields := cachedStructFields(rt)
+	for i := range fields {
+		field := &fields[i].field
+		if isEmbeddedStruct(field) {
+			embeddedOpts, ok := base.enterEmbedded(rt, field, opts)
+			if !ok {
+				continue
+			}
+			keys, err := missingRequiredEmbedded(lookup, base.prefix, field.Type, embeddedOpts...)
+			if err != nil {
+				return nil, err
+			}
+			missing = append(missing, keys...)
+			continue
+		}
+		tag, err := readTag(lookup, base, field, fields[i].tag)
+		if err != nil {
+			return nil, err
+		}
+		if !tag.enabled() {
+			continue
+		}
+		if tag.group != "" {
+			groups = addToGroup(groups, tag)
+			members[tag.group] = append(members[tag.group], tag.key)
+			continue
+		}
+
+		if isStructSlice(field.Type) {
+			keys, err := missingRequiredSlice(lookup, tag, field.Type, opts...)
+			if err != nil {
+				return nil, err
+			}
+			missing = append(missing, keys...)
+			continue
+		}
+		if tag.required && tag.missing() {
+			missing = append(missing, tag.missingKey())
+		}
+	}
+
+	for _, group := range groups {
+		if group.required && len(group.set) == 0 {
+			missing = append(missing, members[group.name]...)
+		}
+	}
+	return missing, nil
+}
+
+// missingRequiredSlice returns the keys of every required field of each
+// element of a slice of structs that is not set in the lookup, along with the
+// key of the slice itself if it is required but has no elements.
+func missingRequiredSlice(lookup lookup, tag *tagOptions, rt reflect.Type, opts ...UnmarshalOption) ([]string, error) {
+	structType := elemType(rt)
+	keys := structKeys(structType, opts...)
+
+	var missing []string
+	i := 0
+	for ; ; i++ {
+		prefix := fmt.Sprintf("%s_%d_", tag.key, i)
+		if !anySet(lookup, prefix, keys) {
+			break
+		}
+		elemOpts := append(opts[:len(opts):len(opts)], withPrefix(prefix))
+		elemMissing, err := missingRequired(lookup, structType, elemOpts...)
+		if err != nil {
+			return nil, err
+		}
+		missing = append(missing, elemMissing...)
+	}
+	if i == 0 && tag.required {
+		missing = append(missing, tag.key)
+	}
+	return missing, nil
+}
+
+// missingRequiredEmbedded returns the keys of every required field of an
+// embedded struct that is not set in the lookup. Embedded pointers are only
+// checked if at least one of their keys is set, mirroring how they are decoded.
+func missingRequiredEmbedded(lookup lookup, prefix string, rt reflect.Type, opts ...UnmarshalOption) ([]string, error) {
+	if rt.Kind() != reflect.Ptr {
+		return missingRequired(lookup, rt, opts...)
+	}
+
+	keys := structKeys(rt.Elem(), opts...)
+	if !anySet(lookup, prefix, keys) {
+		return nil, nil
+	}
+	return missingRequired(lookup, rt.Elem(), opts...)
+}
@@ -0,0 +1,112 @@
+package env_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"rodusek.dev/pkg/env"
+)
+
+func TestMissingRequired(t *testing.T) {
+	type Upstream struct {
+		Host string `env:"HOST,required"`
+		Port int    `env:"PORT"`
+	}
+	type RequiredEnv struct {
+		Name      string     `env:"NAME,required"`
+		Port      int        `env:"PORT,required"`
+		Optional  string     `env:"OPTIONAL"`
+		TLSCert   string     `env:"TLS_CERT,requiredIf=TLS_ENABLED"`
+		Metrics   string     `env:"METRICS,required,feature=metrics"`
+		APIKey    string     `env:"API_KEY,group=auth,required"`
+		Token     string     `env:"TOKEN,group=auth"`
+		Upstreams []Upstream `env:"UPSTREAM"`
+	}
+
+	testCases := []struct {
+		name        string
+		environment env.Environment
+		opts        []env.UnmarshalOption
+		want        []string
+	}{
+		{
+			name:        "All set",
+			environment: env.Environment{"NAME": "example", "PORT": "80", "TOKEN": "token"},
+			want:        nil,
+		}, {
+			name:        "Reports every missing key",
+			environment: env.Environment{"API_KEY": "key"},
+			want:        []string{"NAME", "PORT"},
+		}, {
+			name:        "Required group unset",
+			environment: env.Environment{"NAME": "example", "PORT": "80"},
+			want:        []string{"API_KEY", "TOKEN"},
+		}, {
+			name:        "Condition met",
+			environment: env.Environment{"NAME": "example", "PORT": "80", "API_KEY": "key", "TLS_ENABLED": "true"},
+			want:        []string{"TLS_CERT"},
+		}, {
+			name:        "Feature enabled",
+			environment: env.Environment{"NAME": "example", "PORT": "80", "API_KEY": "key"},
+			opts:        []env.UnmarshalOption{env.WithFeatures("metrics")},
+			want:        []string{"METRICS"},
+		}, {
+			name:        "Struct slice elements",
+			environment: env.Environment{"NAME": "example", "PORT": "80", "API_KEY": "key", "UPSTREAM_0_PORT": "80"},
+			want:        []string{"UPSTREAM_0_HOST"},
+		}, {
+			name:        "Empty values with RequireNonEmpty",
+			environment: env.Environment{"NAME": "", "PORT": "80", "API_KEY": "key"},
+			opts:        []env.UnmarshalOption{env.RequireNonEmpty()},
+			want:        []string{"NAME"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append([]env.UnmarshalOption{env.WithLookup(tc.environment.LookupEnv)}, tc.opts...)
+			out := RequiredEnv{Optional: "unchanged"}
+
+			got, err := env.MissingRequired(&out, opts...)
+			if err != nil {
+				t.Fatalf("MissingRequired(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("MissingRequired(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := out, (RequiredEnv{Optional: "unchanged"}); !cmp.Equal(got, want) {
+				t.Errorf("MissingRequired(%s): modified input to '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestMissingRequired_NotAStruct_ReturnsError(t *testing.T) {
+	_, err := env.MissingRequired(42)
+
+	if got, want := err, env.ErrInvalidType; !errors.Is(got, want) {
+		t.Errorf("MissingRequired(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestMissingRequired_Require_ReportsSelectedKeys(t *testing.T) {
+	var out struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+		Name string `env:"NAME,required"`
+	}
+	lookup := env.WithLookup(func(string) (string, bool) {
+		return "", false
+	})
+
+	got, err := env.MissingRequired(&out, lookup, env.Require("PORT"))
+	if err != nil {
+		t.Fatalf("MissingRequired(): unexpected error: %v", err)
+	}
+
+	if want := []string{"PORT", "NAME"}; !cmp.Equal(got, want) {
+		t.Errorf("MissingRequired(): got '%v', want '%v'", got, want)
+	}
+}
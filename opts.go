@@ -1,5 +1,12 @@
 package env
 
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // UnmarshalOption is an option that can be passed to the [Unmarshal] or
 // [Environment.Unmarshal] functions.
 type UnmarshalOption interface {
@@ -12,6 +19,17 @@ func (a apply) apply(tag *tagOptions) {
 	a(tag)
 }
 
+func (a apply) applyMarshal(tag *tagOptions) {
+	a(tag)
+}
+
+// Option is an option that can be passed to both the [Unmarshal] and
+// [Marshal] families of functions.
+type Option interface {
+	UnmarshalOption
+	MarshalOption
+}
+
 // Separator returns an [UnmarshalOption] that sets the default separator for
 // splitting values for slice values.
 //
@@ -23,3 +41,323 @@ func Separator(sep string) UnmarshalOption {
 		tag.sep = sep
 	})
 }
+
+// KeyValueSeparator returns an [UnmarshalOption] that sets the default
+// separator between the key and value of each entry for map values.
+//
+// Like [Separator], this is the _only_ way to set a custom key-value separator
+// when using [Value]'s unmarshal functionality, since values cannot provide the
+// `env` kvsep tag.
+func KeyValueSeparator(sep string) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.kvsep = sep
+	})
+}
+
+// Base returns an [UnmarshalOption] that parses integer values in the given
+// base, rather than inferring the base from the prefix of the value.
+//
+// Like [Separator], this is the _only_ way to set a fixed base when using
+// [Value]'s unmarshal functionality, since values cannot provide the `env` base
+// tag. An invalid base is reported as a [ParseError].
+func Base(base int) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.base = base
+	})
+}
+
+// DurationUnit returns an [UnmarshalOption] that accepts durations given as
+// bare integers, multiplying them by the unit, as with the `unit` tag option.
+// Durations that include a unit, such as "5s", are still parsed as-is.
+//
+// Like [Separator], this is the _only_ way to set a unit when using [Value]'s
+// unmarshal functionality, since values cannot provide the `env` unit tag.
+func DurationUnit(unit time.Duration) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.unit = unit
+	})
+}
+
+// DecimalOnly returns an [UnmarshalOption] that parses all integer values in
+// base 10. By default, the base is inferred from the prefix of the value, which
+// means that a leading zero denotes octal: "010" is decoded as 8, not 10. With
+// this option, leading zeros are treated as decimal, and prefixes such as "0x"
+// are rejected. This is equivalent to Base(10).
+//
+// Fields with an explicit `base` tag option still use their own base.
+func DecimalOnly() UnmarshalOption {
+	return Base(10)
+}
+
+// WithFeatures returns an [UnmarshalOption] that enables the named features.
+//
+// Fields tagged with the `feature` option are only decoded if the named feature
+// has been enabled. Fields gated behind a disabled feature are skipped entirely:
+// their environment variable is never read, and they are never considered
+// required.
+func WithFeatures(features ...string) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		if tag.features == nil {
+			tag.features = make(map[string]struct{}, len(features))
+		}
+		for _, feature := range features {
+			tag.features[feature] = struct{}{}
+		}
+	})
+}
+
+// Require returns an [UnmarshalOption] that marks the fields with the given
+// keys as required, as if they were tagged with the `required` option. This
+// allows the same struct to be decoded with different requirements in
+// different contexts, such as stricter requirements in production, without
+// editing its tags. Fields that are already required by their tag stay
+// required.
+//
+// Keys are matched against the full key of each field, including any prefix.
+// Keys that do not belong to any field are ignored.
+func Require(keys ...string) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		if tag.requiredKeys == nil {
+			tag.requiredKeys = make(map[string]struct{}, len(keys))
+		}
+		for _, key := range keys {
+			tag.requiredKeys[key] = struct{}{}
+		}
+	})
+}
+
+// TagDecoder decodes the value of an environment variable into out, which is a
+// pointer to the field being decoded.
+type TagDecoder func(value string, out any) error
+
+// WithTagDecoder returns an [UnmarshalOption] that decodes fields tagged with
+// the named option using the decoder, rather than the built-in decoding. This
+// allows formats such as YAML to be supported without this package depending
+// on them; see the yamlenv package for an example.
+//
+// The name must not be that of a built-in tag option, nor contain '='. Errors
+// returned by the decoder are reported as a [ParseError].
+func WithTagDecoder(name string, decoder TagDecoder) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		if tag.decoders == nil {
+			tag.decoders = make(map[string]TagDecoder)
+		}
+		tag.decoders[name] = decoder
+	})
+}
+
+// MarshalOption is an option that can be passed to the [Marshal] or [Template]
+// functions.
+type MarshalOption interface {
+	applyMarshal(*tagOptions)
+}
+
+type applyMarshal func(*tagOptions)
+
+func (a applyMarshal) applyMarshal(tag *tagOptions) {
+	a(tag)
+}
+
+// OmitEmpty returns a [MarshalOption] that omits every field that is empty, as
+// if each field were tagged with the `omitempty` option. Fields that are tagged
+// `required` are still always emitted.
+func OmitEmpty() MarshalOption {
+	return applyMarshal(func(tag *tagOptions) {
+		tag.omitEmpty = true
+	})
+}
+
+// unmarshalOptions adapts the marshal options so that they may be applied
+// while parsing tag options.
+func unmarshalOptions(opts []MarshalOption) []UnmarshalOption {
+	result := make([]UnmarshalOption, 0, len(opts))
+	for _, opt := range opts {
+		result = append(result, apply(opt.applyMarshal))
+	}
+	return result
+}
+
+// ReportUnused returns an [UnmarshalOption] that stores the keys of any
+// environment variables that were present but not consumed by any struct
+// field into unused, sorted by key. This is useful for catching typos in
+// deployment configs.
+//
+// This is only honored by [Environment.Unmarshal] and
+// [SealedEnvironment.Unmarshal], where the full set of keys is known. It has no
+// effect on [Unmarshal], since the process environment contains many unrelated
+// system variables. The keys are only reported if unmarshaling succeeds.
+func ReportUnused(unused *[]string) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.unused = unused
+	})
+}
+
+// DisallowUnknownKeys returns an [UnmarshalOption] that causes unmarshaling to
+// fail with an [UnknownKeyError] if the environment contains any keys that were
+// not consumed by a struct field. This mirrors
+// [encoding/json.Decoder.DisallowUnknownFields].
+//
+// Like [ReportUnused], this is only honored by [Environment.Unmarshal] and
+// [SealedEnvironment.Unmarshal], and has no effect on [Unmarshal].
+func DisallowUnknownKeys() UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.disallowUnknown = true
+	})
+}
+
+// ExtendedBool returns an [UnmarshalOption] that additionally accepts the
+// tokens "yes", "no", "on", "off", "enabled", and "disabled" when decoding bool
+// values, compared case-insensitively. All values accepted by
+// [strconv.ParseBool] are still accepted.
+//
+// Any other value still produces a [ParseError].
+func ExtendedBool() UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.parseBool = parseExtendedBool
+	})
+}
+
+func parseExtendedBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "on", "enabled":
+		return true, nil
+	case "no", "off", "disabled":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// BoolParser returns an [UnmarshalOption] that parses bool values with the
+// given function instead of [strconv.ParseBool]. This may be used to accept
+// locale-specific vocabularies that [ExtendedBool] does not cover.
+//
+// Any error returned from parse is reported as a [ParseError].
+func BoolParser(parse func(string) (bool, error)) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.parseBool = parse
+	})
+}
+
+// WithLookup returns an [UnmarshalOption] that reads environment variables
+// with the given function instead of [os.LookupEnv]. This may be used to back
+// [Unmarshal] with another source, such as a secret store or a layered
+// resolver, without constructing an [Environment].
+//
+// When used with [Environment.Unmarshal] or [SealedEnvironment.Unmarshal], the
+// entries of the environment always take precedence, and fn is only consulted
+// for keys that are not present in it. For an [Environment] this replaces the
+// fallback to the real environment.
+func WithLookup(fn func(key string) (string, bool)) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.lookup = fn
+		tag.lookupContext = nil
+	})
+}
+
+// WithLookupContext is like [WithLookup], but reads environment variables with
+// a function that is given the context passed to [UnmarshalContext], or
+// [context.Background] when unmarshaling without one. This allows a slow
+// lookup, such as one that reads from a remote secret store, to be cancelled.
+//
+// If fn returns an error, no further lookups are made and unmarshaling fails
+// with that error.
+func WithLookupContext(fn func(ctx context.Context, key string) (string, bool, error)) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.lookupContext = fn
+		tag.lookup = nil
+	})
+}
+
+// VerboseErrors returns an [UnmarshalOption] that includes the raw values of
+// fields in the messages of any [ParseError], which may aid debugging.
+//
+// By default, values are masked in error messages so that they do not leak
+// into logs, and are only available programmatically through
+// [ParseError.Value]. The values of fields tagged with the `secret` option are
+// never included, even with this option.
+func VerboseErrors() UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.verboseErrors = true
+	})
+}
+
+// RequireNonEmpty returns an [UnmarshalOption] that treats any required field
+// that is set to an empty value as missing, reporting it with a
+// [RequirementError]. This is equivalent to adding the `nonempty` option to
+// every required field, and catches deployments where a variable exists but
+// was never populated.
+//
+// This only affects fields that are both required and set to an empty value.
+func RequireNonEmpty() UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.nonEmpty = true
+	})
+}
+
+// CSV returns an [UnmarshalOption] that splits slice values as a single
+// CSV-style record, using the separator as the delimiter. This allows
+// individual elements to contain the separator by quoting them, such as
+// `a,"b,c",d`. This is equivalent to adding the `csv` option to every field.
+//
+// The separator must be a single character when this option is used;
+// otherwise, a [ParseError] is returned.
+func CSV() UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.csv = true
+	})
+}
+
+// Escape returns an [UnmarshalOption] that splits slice values only on
+// separators that are not escaped with a backslash, so that `a\,b,c` is split
+// into "a,b" and "c". This is equivalent to adding the `escape` option to every
+// field, and has no effect on fields that use the `csv` option.
+func Escape() UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.escape = true
+	})
+}
+
+// NameFunc returns an [Option] that derives the keys of untagged fields from
+// their names with the given function, instead of converting them to
+// screaming snake case. This may be used to read variables that do not follow
+// the default convention without tagging every field, such as by using the
+// field names verbatim:
+//
+//	env.Unmarshal(&cfg, env.NameFunc(func(name string) string { return name }))
+//
+// Fields with an explicit key in their `env` tag are unaffected.
+func NameFunc(fn func(field string) string) Option {
+	return apply(func(tag *tagOptions) {
+		tag.nameFunc = fn
+	})
+}
+
+// FileFallback returns an [UnmarshalOption] that reads the value of any field
+// whose key is not set from the file named by the same key with a `_FILE`
+// suffix. For example, if `DB_PASSWORD` is not set but
+// `DB_PASSWORD_FILE=/run/secrets/db_password` is, then the contents of that
+// file are used as the value of `DB_PASSWORD`. This is the convention used by
+// Docker and systemd to expose secrets as files.
+//
+// A single trailing newline is trimmed from the contents of the file. The key
+// itself always takes precedence over the file, and a file that cannot be read
+// is reported as a [ParseError] for the `_FILE` key.
+func FileFallback() UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.fileFallback = true
+	})
+}
+
+// WithTracer returns an [UnmarshalOption] that calls trace for each field as
+// its key is resolved, with whether the key was found and its value. This is
+// a diagnostic hook for debugging why a field was or was not populated, such
+// as by logging each key that was consulted.
+//
+// The value of fields tagged with the `secret` option is always redacted, and
+// the value is empty if the key was not found. Fields gated behind a disabled
+// feature are never traced, since they are never looked up.
+func WithTracer(trace func(key string, found bool, value string)) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.tracer = trace
+	})
+}
@@ -0,0 +1,115 @@
+package env
+
+import (
+	"sync"
+	"time"
+)
+
+// ParsedValue is a memoizing view of a [Value] that parses the value at most
+// once per type, and caches the result of each conversion for later calls.
+//
+// This is useful in hot paths where the same value is converted repeatedly,
+// since the conversions of [Value] are otherwise performed on every call.
+// Copies of a ParsedValue share the same cache, and it is safe to use
+// concurrently from multiple goroutines.
+type ParsedValue struct {
+	value Value
+	cache *parsedCache
+}
+
+// parsedCache holds the results of each conversion of a [ParsedValue].
+type parsedCache struct {
+	mu      sync.Mutex
+	results map[string]parsedResult
+}
+
+// parsedResult is the memoized result of a single conversion.
+type parsedResult struct {
+	value any
+	err   error
+}
+
+// Parsed returns a [ParsedValue] that lazily parses and caches conversions of
+// this value.
+func (v Value) Parsed() ParsedValue {
+	return ParsedValue{
+		value: v,
+		cache: &parsedCache{},
+	}
+}
+
+// parsed returns the cached result of the named conversion, computing and
+// storing it with convert on the first call.
+func parsed[T any](p ParsedValue, name string, convert func() (T, error)) (T, error) {
+	if p.cache == nil {
+		return convert()
+	}
+
+	p.cache.mu.Lock()
+	defer p.cache.mu.Unlock()
+	if result, ok := p.cache.results[name]; ok {
+		return result.value.(T), result.err
+	}
+
+	value, err := convert()
+	if p.cache.results == nil {
+		p.cache.results = make(map[string]parsedResult)
+	}
+	p.cache.results[name] = parsedResult{
+		value: value,
+		err:   err,
+	}
+	return value, err
+}
+
+// Value returns the underlying value.
+func (p ParsedValue) Value() Value {
+	return p.value
+}
+
+// String returns the value as a string.
+func (p ParsedValue) String() string {
+	return string(p.value)
+}
+
+// Bool returns the value as a bool, as if by [Value.Bool].
+func (p ParsedValue) Bool() (bool, error) {
+	return parsed(p, "bool", func() (bool, error) {
+		return p.value.Bool()
+	})
+}
+
+// Int returns the value as an int, as if by [Value.Int].
+func (p ParsedValue) Int() (int, error) {
+	return parsed(p, "int", p.value.Int)
+}
+
+// Int64 returns the value as an int64, as if by [Value.Int64].
+func (p ParsedValue) Int64() (int64, error) {
+	return parsed(p, "int64", p.value.Int64)
+}
+
+// Uint returns the value as a uint, as if by [Value.Uint].
+func (p ParsedValue) Uint() (uint, error) {
+	return parsed(p, "uint", p.value.Uint)
+}
+
+// Uint64 returns the value as a uint64, as if by [Value.Uint64].
+func (p ParsedValue) Uint64() (uint64, error) {
+	return parsed(p, "uint64", p.value.Uint64)
+}
+
+// Float64 returns the value as a float64, as if by [Value.Float64].
+func (p ParsedValue) Float64() (float64, error) {
+	return parsed(p, "float64", p.value.Float64)
+}
+
+// Duration returns the value as a [time.Duration], as if by [Value.Duration].
+func (p ParsedValue) Duration() (time.Duration, error) {
+	return parsed(p, "duration", p.value.Duration)
+}
+
+// Time returns the value as a [time.Time], as if by [Value.Time].
+func (p ParsedValue) Time() (time.Time, error) {
+	return parsed(p, "time", p.value.Time)
+}
@@ -0,0 +1,151 @@
+package env_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"rodusek.dev/pkg/env"
+)
+
+func TestParsedValue(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		convert func(env.ParsedValue) (any, error)
+		want    any
+		wantErr error
+	}{
+		{
+			name:    "Bool",
+			value:   env.Value("true"),
+			convert: func(p env.ParsedValue) (any, error) { return p.Bool() },
+			want:    true,
+		}, {
+			name:    "Int",
+			value:   env.Value("42"),
+			convert: func(p env.ParsedValue) (any, error) { return p.Int() },
+			want:    42,
+		}, {
+			name:    "Int64",
+			value:   env.Value("-42"),
+			convert: func(p env.ParsedValue) (any, error) { return p.Int64() },
+			want:    int64(-42),
+		}, {
+			name:    "Uint",
+			value:   env.Value("42"),
+			convert: func(p env.ParsedValue) (any, error) { return p.Uint() },
+			want:    uint(42),
+		}, {
+			name:    "Uint64",
+			value:   env.Value("42"),
+			convert: func(p env.ParsedValue) (any, error) { return p.Uint64() },
+			want:    uint64(42),
+		}, {
+			name:    "Float64",
+			value:   env.Value("4.2"),
+			convert: func(p env.ParsedValue) (any, error) { return p.Float64() },
+			want:    4.2,
+		}, {
+			name:    "Duration",
+			value:   env.Value("5s"),
+			convert: func(p env.ParsedValue) (any, error) { return p.Duration() },
+			want:    5 * time.Second,
+		}, {
+			name:    "Time",
+			value:   env.Value("2021-01-01T00:00:00Z"),
+			convert: func(p env.ParsedValue) (any, error) { return p.Time() },
+			want:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		}, {
+			name:    "Invalid Int",
+			value:   env.Value("not_an_int"),
+			convert: func(p env.ParsedValue) (any, error) { return p.Int() },
+			want:    0,
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := tc.value.Parsed()
+
+			for i := 0; i < 2; i++ {
+				got, err := tc.convert(sut)
+
+				if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+					t.Fatalf("ParsedValue.%s(): got error '%v', want error '%v'", tc.name, got, want)
+				}
+				if got, want := got, tc.want; !cmp.Equal(got, want) {
+					t.Errorf("ParsedValue.%s(): got '%v', want '%v'", tc.name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParsedValue_ZeroValue_DoesNotPanic(t *testing.T) {
+	var sut env.ParsedValue
+
+	got, err := sut.Int()
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Fatalf("ParsedValue.Int(): got error '%v', want error '%v'", got, want)
+	}
+	if got, want := got, 0; got != want {
+		t.Errorf("ParsedValue.Int(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestParsedValue_Concurrent(t *testing.T) {
+	sut := env.Value("42").Parsed()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := sut.Int(); err != nil || got != 42 {
+				t.Errorf("ParsedValue.Int(): got '%v', '%v', want '42', nil", got, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkValue_Int(b *testing.B) {
+	value := env.Value("12345")
+	for i := 0; i < b.N; i++ {
+		if _, err := value.Int(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParsedValue_Int(b *testing.B) {
+	value := env.Value("12345").Parsed()
+	for i := 0; i < b.N; i++ {
+		if _, err := value.Int(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValue_Duration(b *testing.B) {
+	value := env.Value("1h30m")
+	for i := 0; i < b.N; i++ {
+		if _, err := value.Duration(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParsedValue_Duration(b *testing.B) {
+	value := env.Value("1h30m").Parsed()
+	for i := 0; i < b.N; i++ {
+		if _, err := value.Duration(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldStatus describes how the key of a field was resolved by
+// [UnmarshalReport].
+type FieldStatus int
+
+const (
+	// StatusSet indicates that the key was set, and its value was decoded
+	// into the field.
+	StatusSet FieldStatus = iota + 1
+
+	// StatusDefault indicates that the key was not set, and the field kept the
+	// non-zero default value it held before unmarshaling.
+	StatusDefault
+
+	// StatusMissingOptional indicates that the key was not set, and the field
+	// is optional and held its zero value.
+	StatusMissingOptional
+
+	// StatusRequiredError indicates that the key was not set, but the field is
+	// required.
+	StatusRequiredError
+)
+
+// String returns the name of the status, such as "set" or "missing-optional".
+func (s FieldStatus) String() string {
+	switch s {
+	case StatusSet:
+		return "set"
+	case StatusDefault:
+		return "default"
+	case StatusMissingOptional:
+		return "missing-optional"
+	case StatusRequiredError:
+		return "required-error"
+	}
+	return fmt.Sprintf("FieldStatus(%d)", int(s))
+}
+
+// Report maps the keys of the fields resolved by [UnmarshalReport] to how they
+// were resolved.
+type Report map[string]FieldStatus
+
+// UnmarshalReport is like [Unmarshal], but additionally returns a [Report]
+// describing how the key of each field was resolved, such as whether it was set
+// or left at its default. This is useful for making config auditable, such as
+// by printing the report at startup.
+//
+// Slices of structs are reported through the key of the slice itself, which is
+// considered set if any field of its first element is, as well as through the
+// keys of the fields of each element. Fields gated behind a disabled feature
+// are never reported.
+//
+// If unmarshaling fails, the report contains every field that was resolved
+// before the failure, including the failing field itself.
+func UnmarshalReport(out any, opts ...UnmarshalOption) (Report, error) {
+	report := Report{}
+	opts = append(opts[:len(opts):len(opts)], apply(func(tag *tagOptions) {
+		tag.report = report
+	}))
+	err := Unmarshal(out, opts...)
+	return report, err
+}
+
+// status returns how the key of the field with the given value was resolved,
+// before it is decoded.
+func (t *tagOptions) status(rv reflect.Value) FieldStatus {
+	switch {
+	case t.required && t.missing():
+		return StatusRequiredError
+	case t.set:
+		return StatusSet
+	case !rv.IsZero():
+		return StatusDefault
+	}
+	return StatusMissingOptional
+}
+
+// structSliceStatus returns how the key of the slice of structs with the given
+// value was resolved, before it is decoded. The slice has no value of its own,
+// so its key is considered set if any field of its first element is.
+func (t *tagOptions) structSliceStatus(lookup lookup, rt reflect.Type, rv reflect.Value, opts ...UnmarshalOption) FieldStatus {
+	keys := structKeys(elemType(rt), opts...)
+	switch {
+	case anySet(lookup, t.key+"_0_", keys):
+		return StatusSet
+	case t.required:
+		return StatusRequiredError
+	case !rv.IsZero():
+		return StatusDefault
+	}
+	return StatusMissingOptional
+}
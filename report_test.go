@@ -0,0 +1,110 @@
+package env_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"rodusek.dev/pkg/env"
+)
+
+func TestUnmarshalReport(t *testing.T) {
+	type ReportEnv struct {
+		Name     string `env:"REPORT_NAME,required"`
+		Port     int    `env:"REPORT_PORT"`
+		Region   string `env:"REPORT_REGION"`
+		Disabled string `env:"REPORT_DISABLED,feature=disabled"`
+	}
+	setenv(t, "REPORT_NAME=example")
+
+	out := ReportEnv{Port: 8080}
+	got, err := env.UnmarshalReport(&out)
+	if err != nil {
+		t.Fatalf("UnmarshalReport(): unexpected error: %v", err)
+	}
+
+	want := env.Report{
+		"REPORT_NAME":   env.StatusSet,
+		"REPORT_PORT":   env.StatusDefault,
+		"REPORT_REGION": env.StatusMissingOptional,
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("UnmarshalReport(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestUnmarshalReport_RequiredMissing_ReportsError(t *testing.T) {
+	type ReportEnv struct {
+		Region string `env:"REPORT_REGION"`
+		Name   string `env:"REPORT_NAME,required"`
+	}
+
+	var out ReportEnv
+	got, err := env.UnmarshalReport(&out)
+
+	if !errors.Is(err, env.ErrRequirement) {
+		t.Fatalf("UnmarshalReport(): got err '%v', want '%v'", err, env.ErrRequirement)
+	}
+	want := env.Report{
+		"REPORT_REGION": env.StatusMissingOptional,
+		"REPORT_NAME":   env.StatusRequiredError,
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("UnmarshalReport(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestUnmarshalReport_InlineAndStructSlice_ReportsOwnKeys(t *testing.T) {
+	type Database struct {
+		Host string `env:"host"`
+	}
+	type Server struct {
+		Host string `env:"HOST"`
+	}
+	type ReportEnv struct {
+		DB      Database `env:"REPORT_DB,inline=json,required"`
+		Servers []Server `env:"REPORT_SERVERS,required"`
+		Backups []Server `env:"REPORT_BACKUPS"`
+	}
+	setenv(t, `
+		REPORT_DB={"host":"localhost"}
+		REPORT_SERVERS_0_HOST=a.example.com
+	`)
+
+	var out ReportEnv
+	got, err := env.UnmarshalReport(&out)
+	if err != nil {
+		t.Fatalf("UnmarshalReport(): unexpected error: %v", err)
+	}
+
+	want := env.Report{
+		"REPORT_DB":             env.StatusSet,
+		"REPORT_SERVERS":        env.StatusSet,
+		"REPORT_SERVERS_0_HOST": env.StatusSet,
+		"REPORT_BACKUPS":        env.StatusMissingOptional,
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("UnmarshalReport(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestFieldStatusString(t *testing.T) {
+	testCases := []struct {
+		status env.FieldStatus
+		want   string
+	}{
+		{status: env.StatusSet, want: "set"},
+		{status: env.StatusDefault, want: "default"},
+		{status: env.StatusMissingOptional, want: "missing-optional"},
+		{status: env.StatusRequiredError, want: "required-error"},
+		{status: env.FieldStatus(0), want: "FieldStatus(0)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			if got, want := tc.status.String(), tc.want; got != want {
+				t.Errorf("FieldStatus.String(): got '%v', want '%v'", got, want)
+			}
+		})
+	}
+}
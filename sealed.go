@@ -0,0 +1,89 @@
+package env
+
+import (
+	"context"
+	"reflect"
+)
+
+// SealedEnvironment is a map of environment variables that, unlike
+// [Environment], never consults the real process environment.
+//
+// This is useful for hermetic tests and sandboxed config loading, where values
+// leaking in from the real environment would be surprising. Since this is a
+// plain map like [Environment], it may be indexed, ranged over, and converted
+// to and from an [Environment] freely:
+//
+//	sealed := env.SealedEnvironment(environment)
+//
+// Like any map, a sealed environment may be read concurrently, such as by
+// several calls to [SealedEnvironment.Unmarshal], but must not be written to
+// while it is being read. Unlike [Environment], it has no synchronized
+// counterpart, since [SyncEnvironment] falls back to the real environment.
+type SealedEnvironment map[string]Value
+
+// NewSealed creates a new empty sealed environment.
+func NewSealed() SealedEnvironment {
+	return make(SealedEnvironment)
+}
+
+// Get the value of the environment variable with the given key, returning an
+// empty value if it does not exist.
+func (e SealedEnvironment) Get(key string) Value {
+	return e[key]
+}
+
+// Lookup the value of the environment variable with the given key. If the
+// environment variable does not exist, the second return value will be false.
+func (e SealedEnvironment) Lookup(key string) (value Value, ok bool) {
+	value, ok = e[key]
+	return
+}
+
+// Expand replaces ${var} or $var in the string with the values of the
+// variables in this environment, without consulting the real environment.
+// See [Environment.Expand] for more details.
+func (e SealedEnvironment) Expand(s string) string {
+	return expand(s, e.Get)
+}
+
+// Set the value of the environment variable with the given key.
+func (e *SealedEnvironment) Set(key string, value Value) {
+	if *e == nil {
+		*e = make(SealedEnvironment)
+	}
+	(*e)[key] = value
+}
+
+// Unset the environment variable with the given key.
+func (e SealedEnvironment) Unset(key string) {
+	delete(e, key)
+}
+
+// Contains returns true if the environment variable with the given key exists.
+func (e SealedEnvironment) Contains(key string) bool {
+	_, ok := e[key]
+	return ok
+}
+
+// Unmarshal the environment variables into the given struct, without
+// consulting the real environment.
+// See the documentation for [Unmarshal] for more details on what can be
+// returned from this function.
+func (e SealedEnvironment) Unmarshal(out any, opts ...UnmarshalOption) error {
+	rv := reflect.ValueOf(out)
+	tag := newTagOptions(opts...)
+	lookup := tag.lookupOr(nil)
+	if lookup != nil {
+		lookup = Environment(e).lookupOr(lookup)
+	} else {
+		lookup = Environment(e).LookupEnv
+	}
+	return tag.lookupFailed(decodeEnvironment(Environment(e), lookup, rv, opts...))
+}
+
+// UnmarshalContext is like [SealedEnvironment.Unmarshal], but aborts decoding
+// if the given context is cancelled.
+// See the documentation for [UnmarshalContext] for more details.
+func (e SealedEnvironment) UnmarshalContext(ctx context.Context, out any, opts ...UnmarshalOption) error {
+	return e.Unmarshal(out, withContext(ctx, opts)...)
+}
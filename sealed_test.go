@@ -0,0 +1,81 @@
+package env_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"rodusek.dev/pkg/env"
+)
+
+func TestSealedEnvironment_DoesNotFallBack(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "process")
+	sut := env.NewSealed()
+	sut.Set("KEY", "value")
+
+	if got, want := sut.Get("PROCESS_ONLY"), env.Value(""); got != want {
+		t.Errorf("SealedEnvironment.Get(): got '%v', want '%v'", got, want)
+	}
+	if _, ok := sut.Lookup("PROCESS_ONLY"); ok {
+		t.Errorf("SealedEnvironment.Lookup(): got ok 'true', want 'false'")
+	}
+	if sut.Contains("PROCESS_ONLY") {
+		t.Errorf("SealedEnvironment.Contains(): got 'true', want 'false'")
+	}
+
+	if got, want := sut.Get("KEY"), env.Value("value"); got != want {
+		t.Errorf("SealedEnvironment.Get(): got '%v', want '%v'", got, want)
+	}
+	if !sut.Contains("KEY") {
+		t.Errorf("SealedEnvironment.Contains(): got 'false', want 'true'")
+	}
+}
+
+func TestSealedEnvironmentSet_NilEnvironment_Allocates(t *testing.T) {
+	var sut env.SealedEnvironment
+
+	sut.Set("KEY", "value")
+
+	if got, want := sut, (env.SealedEnvironment{"KEY": "value"}); !cmp.Equal(got, want) {
+		t.Errorf("SealedEnvironment.Set(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestSealedEnvironmentUnset(t *testing.T) {
+	sut := env.SealedEnvironment{"KEY": "value"}
+
+	sut.Unset("KEY")
+
+	if sut.Contains("KEY") {
+		t.Errorf("SealedEnvironment.Unset(): got key present, want it removed")
+	}
+}
+
+func TestSealedEnvironmentUnmarshal(t *testing.T) {
+	type SealedEnv struct {
+		Key         string `env:"KEY"`
+		ProcessOnly string `env:"PROCESS_ONLY"`
+	}
+	t.Setenv("PROCESS_ONLY", "process")
+	sut := env.SealedEnvironment{"KEY": "value"}
+
+	var out SealedEnv
+	err := sut.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("SealedEnvironment.Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := out, (SealedEnv{Key: "value"}); !cmp.Equal(got, want) {
+		t.Errorf("SealedEnvironment.Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestSealedEnvironmentExpand_IgnoresProcessEnvironment(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "process")
+	sut := env.SealedEnvironment{"HOST": "localhost"}
+
+	got := sut.Expand("${HOST}:${PROCESS_ONLY}$$")
+
+	if want := "localhost:$"; got != want {
+		t.Errorf("SealedEnvironment.Expand(): got '%v', want '%v'", got, want)
+	}
+}
@@ -0,0 +1,118 @@
+package env
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// byteUnits maps the upper-cased suffixes of a byte size to the number of
+// bytes they represent. Suffixes without an "i" are decimal multiples of 1000,
+// and suffixes with an "i" are binary multiples of 1024.
+var byteUnits = map[string]float64{
+	"":    1,
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+}
+
+// parseByteSize parses a human-readable size, such as "10MB" or "1.5GiB", into
+// a whole number of bytes.
+//
+// The size is a non-negative decimal number, optionally followed by whitespace
+// and a case-insensitive unit. The units "KB", "MB", "GB", and "TB" are decimal
+// multiples of 1000, while "KiB", "MiB", "GiB", and "TiB" are binary multiples
+// of 1024. A number without a unit, or with the unit "B", is a number of bytes.
+// Fractional sizes are accepted only if they amount to a whole number of bytes.
+func parseByteSize(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	end := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if end < 0 {
+		end = len(value)
+	}
+	number, unit := value[:end], strings.TrimSpace(value[end:])
+
+	multiplier, ok := byteUnits[strings.ToUpper(unit)]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q", unit)
+	}
+	if !strings.Contains(number, ".") {
+		size, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if size > math.MaxInt64/int64(multiplier) {
+			return 0, fmt.Errorf("size %q overflows int64", value)
+		}
+		return size * int64(multiplier), nil
+	}
+
+	size, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+	size *= multiplier
+	if size != math.Trunc(size) {
+		return 0, fmt.Errorf("size %q is not a whole number of bytes", value)
+	}
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q overflows int64", value)
+	}
+	return int64(size), nil
+}
+
+// parsePercent parses a percentage, such as "75%" or "12.5 %", into a fraction
+// of one, such as 0.75 or 0.125.
+//
+// The percentage is a decimal number, optionally followed by whitespace, that
+// must end in a "%" sign. The number is scaled in decimal before it is
+// converted, so that "7%" is parsed as exactly the float closest to 0.07.
+func parsePercent(value string, bitSize int) (float64, error) {
+	number, ok := strings.CutSuffix(strings.TrimSpace(value), "%")
+	if !ok {
+		return 0, fmt.Errorf("percentage %q must end in %q", value, "%")
+	}
+	number = strings.TrimSpace(number)
+	if strings.ContainsAny(number, "eExXpP_") {
+		return 0, fmt.Errorf("invalid percentage %q", value)
+	}
+	return strconv.ParseFloat(number+"e-2", bitSize)
+}
+
+// formatPercent formats a fraction of one as a percentage, such as "75%". The
+// decimal point of the shortest representation of the fraction is shifted, so
+// that the percentage is parsed back into the same value by parsePercent.
+func formatPercent(value float64, bitSize int) string {
+	if value == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return strconv.FormatFloat(value*100, 'g', -1, bitSize) + "%"
+	}
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(value, 'e', -1, bitSize), "e")
+	shift, _ := strconv.Atoi(exponent)
+	sign := ""
+	if rest, ok := strings.CutPrefix(mantissa, "-"); ok {
+		sign, mantissa = "-", rest
+	}
+
+	// The mantissa has a single digit before its decimal point, which moves
+	// right by the exponent, and by two more to scale it to a percentage.
+	digits := strings.Replace(mantissa, ".", "", 1)
+	point := 1 + shift + 2
+	switch {
+	case point <= 0:
+		digits = "0." + strings.Repeat("0", -point) + digits
+	case point >= len(digits):
+		digits += strings.Repeat("0", point-len(digits))
+	default:
+		digits = digits[:point] + "." + digits[point:]
+	}
+	return sign + digits + "%"
+}
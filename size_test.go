@@ -0,0 +1,203 @@
+package env_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"rodusek.dev/pkg/env"
+)
+
+func TestValueBytes64(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		want    int64
+		wantErr error
+	}{
+		{name: "No unit", value: "512", want: 512},
+		{name: "Bytes", value: "512B", want: 512},
+		{name: "Kilobytes", value: "10KB", want: 10_000},
+		{name: "Megabytes", value: "10MB", want: 10_000_000},
+		{name: "Gigabytes", value: "2GB", want: 2_000_000_000},
+		{name: "Terabytes", value: "1TB", want: 1_000_000_000_000},
+		{name: "Kibibytes", value: "10KiB", want: 10 << 10},
+		{name: "Mebibytes", value: "10MiB", want: 10 << 20},
+		{name: "Gibibytes", value: "2GiB", want: 2 << 30},
+		{name: "Tebibytes", value: "1TiB", want: 1 << 40},
+		{name: "Lower case unit", value: "10mb", want: 10_000_000},
+		{name: "Whitespace before unit", value: "10 MiB", want: 10 << 20},
+		{name: "Fractional size", value: "1.5GiB", want: 3 << 29},
+		{name: "Fractional bytes", value: "1.5B", wantErr: env.ErrParse},
+		{name: "Unknown unit", value: "10XB", wantErr: env.ErrParse},
+		{name: "Ambiguous unit", value: "10K", wantErr: env.ErrParse},
+		{name: "Negative size", value: "-10MB", wantErr: env.ErrParse},
+		{name: "Missing number", value: "MB", wantErr: env.ErrParse},
+		{name: "Overflow", value: "9999999TiB", wantErr: env.ErrParse},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.Bytes64()
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.Bytes64(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; got != want {
+				t.Errorf("Value.Bytes64(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Bytes(t *testing.T) {
+	type BytesEnv struct {
+		MaxBody   int64  `env:"MAX_BODY,bytes"`
+		CacheSize uint32 `env:"CACHE_SIZE,bytes"`
+		Limits    []int  `env:"LIMITS,bytes"`
+	}
+	sut := env.SealedEnvironment{
+		"MAX_BODY":   "10MB",
+		"CACHE_SIZE": "64KiB",
+		"LIMITS":     "1KB,1KiB",
+	}
+
+	var out BytesEnv
+	if err := sut.Unmarshal(&out); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := BytesEnv{MaxBody: 10_000_000, CacheSize: 64 << 10, Limits: []int{1000, 1024}}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_BytesOverflowsField_ReturnsError(t *testing.T) {
+	type BytesEnv struct {
+		Size uint8 `env:"SIZE,bytes"`
+	}
+	sut := env.SealedEnvironment{"SIZE": "1KB"}
+
+	var out BytesEnv
+	err := sut.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_BytesOnNonInteger_ReturnsError(t *testing.T) {
+	type BytesEnv struct {
+		Size string `env:"SIZE,bytes"`
+	}
+	sut := env.SealedEnvironment{"SIZE": "1KB"}
+
+	var out BytesEnv
+	err := sut.Unmarshal(&out)
+
+	if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestValuePercent(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		want    float64
+		wantErr error
+	}{
+		{name: "Whole percentage", value: "75%", want: 0.75},
+		{name: "Fractional percentage", value: "12.5%", want: 0.125},
+		{name: "Small percentage", value: "7%", want: 0.07},
+		{name: "Zero", value: "0%", want: 0},
+		{name: "Over one hundred", value: "150%", want: 1.5},
+		{name: "Negative percentage", value: "-5%", want: -0.05},
+		{name: "Whitespace before sign", value: "75 %", want: 0.75},
+		{name: "Missing sign", value: "75", wantErr: env.ErrParse},
+		{name: "Missing number", value: "%", wantErr: env.ErrParse},
+		{name: "Exponent", value: "7e1%", wantErr: env.ErrParse},
+		{name: "Not a number", value: "high%", wantErr: env.ErrParse},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.Percent()
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.Percent(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; got != want {
+				t.Errorf("Value.Percent(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Percent(t *testing.T) {
+	type PercentEnv struct {
+		Cache      float64   `env:"CACHE,percent"`
+		Threshold  float32   `env:"THRESHOLD,percent"`
+		Thresholds []float64 `env:"THRESHOLDS,percent"`
+	}
+	sut := env.SealedEnvironment{
+		"CACHE":      "75%",
+		"THRESHOLD":  "12.5%",
+		"THRESHOLDS": "50%,90%",
+	}
+
+	var out PercentEnv
+	if err := sut.Unmarshal(&out); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := PercentEnv{Cache: 0.75, Threshold: 0.125, Thresholds: []float64{0.5, 0.9}}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_PercentOnNonFloat_ReturnsError(t *testing.T) {
+	type PercentEnv struct {
+		Cache int `env:"CACHE,percent"`
+	}
+	sut := env.SealedEnvironment{"CACHE": "75%"}
+
+	var out PercentEnv
+	err := sut.Unmarshal(&out)
+
+	if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestMarshal_Percent_RoundTrips(t *testing.T) {
+	type PercentEnv struct {
+		Cache     float64 `env:"CACHE,percent"`
+		Ratio     float64 `env:"RATIO,percent"`
+		Small     float64 `env:"SMALL,percent"`
+		Threshold float32 `env:"THRESHOLD,percent"`
+		Zero      float64 `env:"ZERO,percent"`
+	}
+	want := PercentEnv{Cache: 0.75, Ratio: 0.07, Small: -0.00001, Threshold: 0.125}
+
+	environment, err := env.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal(): unexpected error: %v", err)
+	}
+	wantEnv := env.Environment{"CACHE": "75%", "RATIO": "7%", "SMALL": "-0.001%", "THRESHOLD": "12.5%", "ZERO": "0%"}
+	if got, want := environment, wantEnv; !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): got '%v', want '%v'", got, want)
+	}
+	var got PercentEnv
+	if err := env.SealedEnvironment(environment).Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("Marshal(): round trip mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
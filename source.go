@@ -0,0 +1,48 @@
+package env
+
+// SourceProcess is the source name of the variables that were read from the
+// real environment by [LoadWithSource].
+const SourceProcess = "process"
+
+// Source is an [Environment] along with a name describing where it came from,
+// such as the path of a dotenv file, or "defaults".
+type Source struct {
+	// Name is the name of the source, which is reported for each variable that
+	// is read from it.
+	Name string
+
+	// Environment holds the variables of the source.
+	Environment Environment
+}
+
+// LoadWithSource loads the current environment variables, as by [Load], layered
+// over the variables of the given sources. It returns the composed environment
+// along with the name of the source of each variable, which is useful for
+// tooling that reports where configuration came from.
+//
+// Variables of the real environment take precedence and are reported with the
+// [SourceProcess] name. The given sources are consulted in order afterwards,
+// so that earlier sources take precedence over later ones, mirroring [Chain]:
+//
+//	environment, sources := env.LoadWithSource(
+//		env.Source{Name: ".env", Environment: fileEnv},
+//		env.Source{Name: "defaults", Environment: defaults},
+//	)
+//	fmt.Printf("PORT=%v (from %v)\n", environment["PORT"], sources["PORT"])
+func LoadWithSource(sources ...Source) (Environment, map[string]string) {
+	environment := Load()
+	names := make(map[string]string, len(environment))
+	for key := range environment {
+		names[key] = SourceProcess
+	}
+	for _, source := range sources {
+		for key, value := range source.Environment {
+			if _, ok := environment[key]; ok {
+				continue
+			}
+			environment[key] = value
+			names[key] = source.Name
+		}
+	}
+	return environment, names
+}
@@ -0,0 +1,78 @@
+package env_test
+
+import (
+	"testing"
+
+	"rodusek.dev/pkg/env"
+)
+
+func TestLoadWithSource(t *testing.T) {
+	t.Setenv("SOURCE_PROCESS", "process")
+	file := env.Source{
+		Name: ".env",
+		Environment: env.Environment{
+			"SOURCE_PROCESS": "file",
+			"SOURCE_FILE":    "file",
+		},
+	}
+	defaults := env.Source{
+		Name: "defaults",
+		Environment: env.Environment{
+			"SOURCE_FILE":     "default",
+			"SOURCE_DEFAULTS": "default",
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		key        string
+		wantValue  env.Value
+		wantSource string
+	}{
+		{
+			name:       "Process takes precedence",
+			key:        "SOURCE_PROCESS",
+			wantValue:  "process",
+			wantSource: env.SourceProcess,
+		}, {
+			name:       "Earlier source takes precedence",
+			key:        "SOURCE_FILE",
+			wantValue:  "file",
+			wantSource: ".env",
+		}, {
+			name:       "Last source",
+			key:        "SOURCE_DEFAULTS",
+			wantValue:  "default",
+			wantSource: "defaults",
+		},
+	}
+
+	environment, sources := env.LoadWithSource(file, defaults)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := environment[tc.key], tc.wantValue; got != want {
+				t.Errorf("LoadWithSource(%s): got value '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := sources[tc.key], tc.wantSource; got != want {
+				t.Errorf("LoadWithSource(%s): got source '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestLoadWithSource_EveryKeyHasSource(t *testing.T) {
+	environment, sources := env.LoadWithSource(env.Source{
+		Name:        "defaults",
+		Environment: env.Environment{"SOURCE_DEFAULTS": "default"},
+	})
+
+	if got, want := len(sources), len(environment); got != want {
+		t.Fatalf("LoadWithSource(): got %v sources, want %v", got, want)
+	}
+	for key := range environment {
+		if _, ok := sources[key]; !ok {
+			t.Errorf("LoadWithSource(): missing source for key '%v'", key)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package env
+
+import (
+	"context"
+	"sync"
+)
+
+// SyncEnvironment is an [Environment] that is safe for concurrent use by
+// multiple goroutines, such as a server that hot-reloads its configuration
+// while serving requests.
+//
+// Like [Environment], lookups fall back to the real environment if a key is
+// not present. The zero value is an empty environment ready to use.
+//
+// A SyncEnvironment must not be copied after first use.
+type SyncEnvironment struct {
+	mu  sync.RWMutex
+	env Environment
+}
+
+// Synchronized returns a [SyncEnvironment] that is backed by this environment.
+// The environment is not copied, so it should no longer be accessed directly
+// once it has been synchronized.
+func (e Environment) Synchronized() *SyncEnvironment {
+	return &SyncEnvironment{
+		env: e,
+	}
+}
+
+// Get the value of the environment variable with the given key, falling back
+// to the real environment as if by using [os.Getenv].
+func (s *SyncEnvironment) Get(key string) Value {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.env.Get(key)
+}
+
+// Lookup the value of the environment variable with the given key, falling
+// back to the real environment as if by using [os.LookupEnv]. If it does not
+// exist in either, the second return value will be false.
+func (s *SyncEnvironment) Lookup(key string) (Value, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.env.Lookup(key)
+}
+
+// Set the value of the environment variable with the given key.
+func (s *SyncEnvironment) Set(key string, value Value) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.env.Set(key, value)
+}
+
+// Unset the environment variable with the given key.
+func (s *SyncEnvironment) Unset(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.env.Unset(key)
+}
+
+// Contains returns true if the environment variable with the given key exists.
+func (s *SyncEnvironment) Contains(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.env.Contains(key)
+}
+
+// Unmarshal the environment variables into the given struct.
+//
+// A read lock is held for the entire duration of unmarshaling, so the struct
+// is always decoded from a consistent snapshot of the environment, and any
+// concurrent writes will block until it completes.
+// See the documentation for [Unmarshal] for more details on what can be
+// returned from this function.
+func (s *SyncEnvironment) Unmarshal(out any, opts ...UnmarshalOption) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.env.Unmarshal(out, opts...)
+}
+
+// UnmarshalContext is like [SyncEnvironment.Unmarshal], but aborts decoding if
+// the given context is cancelled.
+// See the documentation for [UnmarshalContext] for more details.
+func (s *SyncEnvironment) UnmarshalContext(ctx context.Context, out any, opts ...UnmarshalOption) error {
+	return s.Unmarshal(out, withContext(ctx, opts)...)
+}
@@ -0,0 +1,73 @@
+package env_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"rodusek.dev/pkg/env"
+)
+
+func TestSyncEnvironment(t *testing.T) {
+	t.Setenv("PROCESS_ONLY", "process")
+	sut := env.Environment{"KEY": "value"}.Synchronized()
+
+	if got, want := sut.Get("KEY"), env.Value("value"); got != want {
+		t.Errorf("SyncEnvironment.Get(): got '%v', want '%v'", got, want)
+	}
+	if got, want := sut.Get("PROCESS_ONLY"), env.Value("process"); got != want {
+		t.Errorf("SyncEnvironment.Get(): got '%v', want '%v'", got, want)
+	}
+	if _, ok := sut.Lookup("MISSING"); ok {
+		t.Errorf("SyncEnvironment.Lookup(): got ok 'true', want 'false'")
+	}
+
+	sut.Set("OTHER", "other")
+	if !sut.Contains("OTHER") {
+		t.Errorf("SyncEnvironment.Set(): got key missing, want it present")
+	}
+
+	sut.Unset("OTHER")
+	if sut.Contains("OTHER") {
+		t.Errorf("SyncEnvironment.Unset(): got key present, want it removed")
+	}
+}
+
+func TestSyncEnvironment_ZeroValue(t *testing.T) {
+	var sut env.SyncEnvironment
+
+	sut.Set("KEY", "value")
+
+	if got, want := sut.Get("KEY"), env.Value("value"); got != want {
+		t.Errorf("SyncEnvironment.Get(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestSyncEnvironmentUnmarshal_ConcurrentWrites(t *testing.T) {
+	type SyncEnv struct {
+		Name string `env:"NAME"`
+		Port int    `env:"PORT"`
+	}
+	sut := env.Environment{"NAME": "example", "PORT": "0"}.Synchronized()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			sut.Set("PORT", env.Value(fmt.Sprint(i)))
+		}(i)
+		go func() {
+			defer wg.Done()
+			var out SyncEnv
+			if err := sut.Unmarshal(&out); err != nil {
+				t.Errorf("SyncEnvironment.Unmarshal(): unexpected error: %v", err)
+			}
+			if got, want := out.Name, "example"; !cmp.Equal(got, want) {
+				t.Errorf("SyncEnvironment.Unmarshal(): got '%v', want '%v'", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
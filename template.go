@@ -0,0 +1,179 @@
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Template generates an example dotenv file from the given config struct,
+// which is useful as a starting point for documenting or onboarding a new
+// environment.
+//
+// Every field that [Unmarshal] would read is emitted on its own line using its
+// computed key, preceded by a comment describing the field's Go type and any
+// requirements placed on it by its tag options. Any field that already holds a
+// non-zero value in the input is emitted with that value as its default, which
+// mirrors how defaults are specified for [Unmarshal]; otherwise the value is
+// left empty. The values of fields tagged with the `secret` option are always
+// left empty, and the `omitempty` option has no effect.
+//
+// Untagged embedded structs are flattened into the template, as they are by
+// [Marshal]. Slices of structs are emitted with indexed keys, such as
+// `UPSTREAM_0_HOST`, for each element; an empty slice is emitted as a single
+// element at index 0, so that the keys of its fields are still documented.
+//
+// The input may be a struct or a pointer to a struct. An [InvalidTypeError] is
+// returned for any other type, or if a default value cannot be encoded.
+func Template(in any, opts ...MarshalOption) ([]byte, error) {
+	if in == nil {
+		return nil, fmt.Errorf("env: cannot generate template from nil value")
+	}
+
+	rv := reflect.ValueOf(in)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv = reflect.New(rv.Type().Elem())
+		}
+		rv = rv.Elem()
+	}
+	rt := rv.Type()
+	if rt.Kind() != reflect.Struct {
+		return nil, &InvalidTypeError{
+			Type: rt,
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := templateStruct(&buf, rv, unmarshalOptions(opts)...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// templateStruct writes an entry for every field of the struct to the buffer.
+// Embedded structs are flattened, and slices of structs are written with
+// indexed keys; an empty slice is written as a single zero element, so that
+// the keys of its fields are still documented.
+func templateStruct(buf *bytes.Buffer, rv reflect.Value, opts ...UnmarshalOption) error {
+	base := newTagOptions(opts...)
+	fields := cachedStructFields(rv.Type())
+	for i := range fields {
+		field := &fields[i].field
+		fv := rv.FieldByIndex(field.Index)
+		if isEmbeddedStruct(field) {
+			embeddedOpts, ok := base.enterEmbedded(rv.Type(), field, opts)
+			if !ok {
+				continue
+			}
+			if err := templateStruct(buf, derefOrZero(fv), embeddedOpts...); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tag, err := applyFieldTag(base, field, fields[i].tag)
+		if err != nil {
+			return err
+		}
+		if isStructSlice(field.Type) {
+			if err := templateStructSlice(buf, tag, fv, opts...); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value := ""
+		if !fv.IsZero() && !tag.secret {
+			if tag.inline != "" {
+				value, _, err = encodeInline(tag, fv, opts...)
+			} else {
+				value, err = encodeValue(tag, fv)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(buf, "# %s\n", strings.Join(annotations(tag, field.Type), ", "))
+		fmt.Fprintf(buf, "%s=%s\n", tag.key, quoteValue(value))
+	}
+	return nil
+}
+
+// templateStructSlice writes the entries of each element of a slice of
+// structs, using keys indexed by a numeric suffix of the tag key.
+func templateStructSlice(buf *bytes.Buffer, tag *tagOptions, rv reflect.Value, opts ...UnmarshalOption) error {
+	rv = derefOrZero(rv)
+	elems := make([]reflect.Value, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		elems = append(elems, derefOrZero(rv.Index(i)))
+	}
+	if len(elems) == 0 {
+		elems = append(elems, reflect.New(elemType(rv.Type())).Elem())
+	}
+	for i, elem := range elems {
+		prefix := fmt.Sprintf("%s_%d_", tag.key, i)
+		elemOpts := append(opts[:len(opts):len(opts)], withPrefix(prefix))
+		if err := templateStruct(buf, elem, elemOpts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// derefOrZero dereferences the pointers of the value, substituting the zero
+// value of the pointed-to type for any nil pointer.
+func derefOrZero(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.New(rv.Type().Elem()).Elem()
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+// annotations describes the type and tag options of a field for a template.
+func annotations(tag *tagOptions, rt reflect.Type) []string {
+	result := []string{rt.String()}
+	if tag.required {
+		result = append(result, "required")
+	}
+	if tag.secret {
+		result = append(result, "secret")
+	}
+	if tag.requiredIf != "" {
+		result = append(result, fmt.Sprintf("required if %s is set", tag.requiredIf))
+	}
+	if tag.group != "" {
+		result = append(result, fmt.Sprintf("exclusive group %s", tag.group))
+	}
+	if tag.feature != "" {
+		result = append(result, fmt.Sprintf("feature %s", tag.feature))
+	}
+	if tag.oneOf != nil {
+		result = append(result, fmt.Sprintf("one of: %s", strings.Join(tag.oneOf, " ")))
+	}
+	if tag.min != nil {
+		result = append(result, fmt.Sprintf("min %v", *tag.min))
+	}
+	if tag.max != nil {
+		result = append(result, fmt.Sprintf("max %v", *tag.max))
+	}
+	return result
+}
+
+// quoteValue quotes the value if it cannot be represented verbatim in a dotenv
+// file.
+func quoteValue(value string) string {
+	if strings.ContainsAny(value, " \t\r\n\"'#\\$=") {
+		return strconv.Quote(value)
+	}
+	return value
+}
@@ -0,0 +1,261 @@
+package env_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"rodusek.dev/pkg/env"
+)
+
+func TestTemplate(t *testing.T) {
+	type TemplateEnv struct {
+		ProjectName string        `env:"PROJECT_NAME,required"`
+		Timeout     time.Duration `env:"TIMEOUT"`
+		Path        []string      `env:"PATH,sep=;"`
+		LogLevel    string        `env:"LOG_LEVEL,oneof=debug info"`
+		Greeting    string
+		Port        *int `env:"PORT,min=1,max=65535"`
+		unexported  string
+	}
+
+	testCases := []struct {
+		name  string
+		input any
+		want  string
+	}{
+		{
+			name:  "Zero values",
+			input: TemplateEnv{},
+			want: `# string, required
+PROJECT_NAME=
+
+# time.Duration
+TIMEOUT=
+
+# []string
+PATH=
+
+# string, one of: debug info
+LOG_LEVEL=
+
+# string
+GREETING=
+
+# *int, min 1, max 65535
+PORT=
+`,
+		}, {
+			name: "Default values",
+			input: &TemplateEnv{
+				Timeout:  5 * time.Second,
+				Path:     []string{"/usr/bin", "/bin"},
+				LogLevel: "info",
+				Greeting: "Hello World",
+				Port:     ptr(8080),
+			},
+			want: `# string, required
+PROJECT_NAME=
+
+# time.Duration
+TIMEOUT=5s
+
+# []string
+PATH=/usr/bin;/bin
+
+# string, one of: debug info
+LOG_LEVEL=info
+
+# string
+GREETING="Hello World"
+
+# *int, min 1, max 65535
+PORT=8080
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := env.Template(tc.input)
+			if err != nil {
+				t.Fatalf("Template(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := string(got), tc.want; got != want {
+				t.Errorf("Template(%s): mismatch (-want +got):\n%s", tc.name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestTemplate_MapField_SortsEntries(t *testing.T) {
+	type MapEnv struct {
+		Labels map[string]int `env:"LABELS,sep=;"`
+	}
+
+	got, err := env.Template(MapEnv{Labels: map[string]int{"b": 2, "a": 1}})
+	if err != nil {
+		t.Fatalf("Template(): unexpected error: %v", err)
+	}
+
+	want := `# map[string]int
+LABELS="a=1;b=2"
+`
+	if got := string(got); got != want {
+		t.Errorf("Template(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestTemplate_CSVField_QuotesEntries(t *testing.T) {
+	type CSVEnv struct {
+		Tags []string `env:"TAGS,csv"`
+	}
+
+	got, err := env.Template(CSVEnv{Tags: []string{"a", "b,c", "d"}})
+	if err != nil {
+		t.Fatalf("Template(): unexpected error: %v", err)
+	}
+
+	want := `# []string
+TAGS="a,\"b,c\",d"
+`
+	if got := string(got); got != want {
+		t.Errorf("Template(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestTemplate_SecretField_OmitsValue(t *testing.T) {
+	type SecretEnv struct {
+		APIToken string `env:"API_TOKEN,required,secret"`
+	}
+
+	got, err := env.Template(SecretEnv{APIToken: "hunter2"})
+	if err != nil {
+		t.Fatalf("Template(): unexpected error: %v", err)
+	}
+
+	want := `# string, required, secret
+API_TOKEN=
+`
+	if got := string(got); got != want {
+		t.Errorf("Template(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestTemplate_NotAStruct_ReturnsError(t *testing.T) {
+	_, err := env.Template(42)
+
+	if got, want := err, env.ErrInvalidType; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Template(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestTemplate_IgnoredField_IsOmitted(t *testing.T) {
+	type IgnoredEnv struct {
+		Name  string `env:"NAME"`
+		Cache string `env:"-"`
+	}
+
+	got, err := env.Template(IgnoredEnv{Cache: "value"})
+	if err != nil {
+		t.Fatalf("Template(): unexpected error: %v", err)
+	}
+
+	want := `# string
+NAME=
+`
+	if got := string(got); got != want {
+		t.Errorf("Template(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestTemplate_NestedStructs(t *testing.T) {
+	type Common struct {
+		Region string `env:"REGION"`
+	}
+	type Optional struct {
+		Zone string `env:"ZONE"`
+	}
+	type Upstream struct {
+		Host string `env:"HOST,required"`
+	}
+	type NestedEnv struct {
+		Common
+		*Optional
+		Upstreams []Upstream `env:"UPSTREAM"`
+	}
+
+	testCases := []struct {
+		name  string
+		input NestedEnv
+		want  string
+	}{
+		{
+			name:  "Zero values",
+			input: NestedEnv{},
+			want: `# string
+REGION=
+
+# string
+ZONE=
+
+# string, required
+UPSTREAM_0_HOST=
+`,
+		}, {
+			name: "Default values",
+			input: NestedEnv{
+				Common:    Common{Region: "us"},
+				Optional:  &Optional{Zone: "east"},
+				Upstreams: []Upstream{{Host: "a"}, {Host: "b"}},
+			},
+			want: `# string
+REGION=us
+
+# string
+ZONE=east
+
+# string, required
+UPSTREAM_0_HOST=a
+
+# string, required
+UPSTREAM_1_HOST=b
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := env.Template(tc.input)
+			if err != nil {
+				t.Fatalf("Template(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := string(got), tc.want; got != want {
+				t.Errorf("Template(%s): mismatch (-want +got):\n%s", tc.name, cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestTemplate_InlineField_EncodesDefault(t *testing.T) {
+	type Database struct {
+		Host string `env:"host"`
+		Port int    `env:"port"`
+	}
+	type InlineEnv struct {
+		DB Database `env:"DB,inline=json"`
+	}
+
+	got, err := env.Template(InlineEnv{DB: Database{Host: "localhost", Port: 5432}})
+	if err != nil {
+		t.Fatalf("Template(): unexpected error: %v", err)
+	}
+
+	want := "# env_test.Database\nDB=\"{\\\"host\\\":\\\"localhost\\\",\\\"port\\\":\\\"5432\\\"}\"\n"
+	if got, want := string(got), want; got != want {
+		t.Errorf("Template(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
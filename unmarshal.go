@@ -1,16 +1,35 @@
 package env
 
 import (
+	"context"
+	"database/sql"
 	"encoding"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"os"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
-	"unicode"
+	"unicode/utf8"
+
+	"rodusek.dev/pkg/env/internal/dotenv"
 )
 
+// Validator is an interface that allows types to validate themselves once they
+// have been decoded.
+type Validator interface {
+	// Validate returns an error if the value is not valid.
+	Validate() error
+}
+
 // Unmarshaler is an interface that allows for custom unmarshaling of
 // environment variables.
 type Unmarshaler interface {
@@ -22,121 +41,993 @@ type Unmarshaler interface {
 // Unmarshal reads values from the current environment and parses values into
 // the provided output struct.
 //
-// Fields on the output struct are interpreted based on `env` tag options which
-// defines the variable key to read from, and any additional options.
-// If this tag is not set, the field name is converted to screaming
-// snake case and used instead (e.g. the field `ProjectName` would use the
-// environment variable `PROJECT_NAME`). Unexported fields are ignored.
+// Each exported field is read from the key named by its `env` tag, or from its
+// name in screaming snake case if it is untagged, and fields tagged `env:"-"`
+// are ignored. The key may be followed by comma-separated options:
 //
-// A nil `out` parameter is valid and will return nil without error.
-//
-// This function supports parsing values from the environment for the following
-// types:
+//   - requirements: `required`, `nonempty`, `requiredIf`, `group`, `feature`
+//   - slices and maps: `sep`, `kvsep`, `csv`, `escape`, `unique`, `minlen`,
+//     `maxlen`
+//   - transforms: `trim`, `trimprefix`, `trimsuffix`, `lower`, `upper`,
+//     `concat`, `join`
+//   - formats: `string`, `inline`, `base`, `bytes`, `percent`, `unit`, `tz`,
+//     `presence`
+//   - validation: `oneof`, `min`, `max`
+//   - handling: `secret`
 //
-//   - string types
-//   - integral types (byte, int, int8, int16, int32, int64, uint, uint8,
-//     uint16, uint32, uint64)
-//   - floating point types (float32, float64)
-//   - boolean types
-//   - [time.Duration] (using [time.ParseDuration] format)
-//   - [time.Time] (using [time.Parse], using all common time format layouts)
-//   - [Unmarshaler]
-//   - [encoding.TextUnmarshaler]
-//   - slices of any of the above supported types
+// Any other option names a decoder supplied with [WithTagDecoder]. See the
+// package documentation for the supported types and the details of each
+// option.
 //
-// This makes use of the `env` tag to specify the environment variable key to
-// read from.
+// A nil `out` parameter is valid and will return nil without error.
 //
-// Fields may be marked as required by adding the `required` option to the tag.
-// Slices may have custom separators (default is ',') that may be specified with
-// the `sep` option. For example:
+// For example:
 //
 //	type Environment struct {
 //		ProjectName string        `env:"PROJECT_NAME,required"`
 //		Timeout     time.Duration `env:"TIMEOUT"`
 //		Path        []string      `env:"PATH,required,sep=;"`
+//		TLSCert     string        `env:"TLS_CERT,requiredIf=TLS_ENABLED"`
+//		APIKey      string        `env:"API_KEY,group=auth,required"`
+//		OAuthToken  string        `env:"OAUTH_TOKEN,group=auth"`
+//		Port        int           `env:"PORT,trim,min=1,max=65535"`
+//		LogLevel    string        `env:"LOG_LEVEL,lower,oneof=debug info warn error"`
 //	}
 //
 // On error, this function may return one of the following error types:
 //
 //   - [RequirementError] when a required environment variable was not defined.
 //   - [ParseError] when a value cannot be parsed from an environment variable.
+//     If a single element of a slice or array cannot be parsed, it wraps an
+//     [IndexError] that identifies the element and its value.
 //   - [InvalidTypeError] when an unsupported type is used without defining it
 //     as a [Marshaler] or [encoding.TextUnmarshaler].
 //   - [InvalidTagOptionError] when an invalid/unsupported tag option is used.
+//   - [ExclusiveGroupError] when a mutually-exclusive group is violated.
+//   - [ValidationError] when a value violates a constraint from its tag, or
+//     when the Validate method of a [Validator] returns an error.
+//   - [UnknownKeyError] when [DisallowUnknownKeys] is used and an environment
+//     contains keys that were not consumed.
 func Unmarshal(out any, opts ...UnmarshalOption) error {
-	// Nothing in, no error taking it out. Seems reasonable?
+	return NewDecoder(opts...).Decode(out)
+}
+
+// UnmarshalContext is like [Unmarshal], but aborts decoding if the given
+// context is cancelled. The context is checked before each field is decoded,
+// and the context's error is returned if it has been cancelled or its deadline
+// has exceeded.
+//
+// The context is also passed to the lookup supplied with [WithLookupContext],
+// so that a slow lookup, such as one that reads from a remote secret store,
+// may be interrupted. Lookups supplied with [WithLookup], and the real
+// environment, are only interrupted between fields.
+func UnmarshalContext(ctx context.Context, out any, opts ...UnmarshalOption) error {
 	if out == nil {
 		return nil
 	}
 
+	opts = withContext(ctx, opts)
 	rv := reflect.ValueOf(out)
-	return decode(os.LookupEnv, rv, opts...)
+	tag := newTagOptions(opts...)
+	return tag.lookupFailed(decode(tag.lookupOr(os.LookupEnv), rv, opts...))
+}
+
+// UnmarshalReader parses the reader as dotenv text, in which each line assigns
+// a value to a key such as `KEY=value`, and unmarshals the parsed variables into
+// out. This is a convenience for loading configuration from an embedded file or
+// a request body, and behaves like [Environment.Unmarshal] on the parsed
+// variables; in particular, keys that are not in the text are looked up in the
+// real environment.
+//
+// Malformed text is reported as a [SyntaxError], which is distinct from any of
+// the errors returned by [Unmarshal] for the parsed values. Errors from reading
+// r are returned as-is.
+func UnmarshalReader(r io.Reader, out any, opts ...UnmarshalOption) error {
+	entries, err := dotenv.Parse(r)
+	if err != nil {
+		var syntaxErr *dotenv.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return &SyntaxError{
+				Line: syntaxErr.Line,
+				Msg:  syntaxErr.Msg,
+			}
+		}
+		return err
+	}
+	return FromMap(entries).Unmarshal(out, opts...)
 }
 
 // lookup is a function that performs a string lookup on the environment.
 // This is used internally to allow Unmarshal to be used with a custom env.
 type lookup func(key string) (string, bool)
 
+// keyLister is implemented by sources of environment variables that are able
+// to enumerate their keys, such as [Environment]. Decoding features that need
+// to discover keys, rather than look up known ones, are only available for
+// sources that implement it. The real environment deliberately does not, since
+// it contains many unrelated system variables.
+type keyLister interface {
+	Keys() []string
+}
+
+var _ keyLister = Environment(nil)
+
 type tagOptions struct {
 	key      string
 	value    string
 	set      bool
 	required bool
 	sep      string
+	kvsep    string
+	feature  string
+	features map[string]struct{}
+
+	// requiredKeys are the keys of the fields that are required regardless of
+	// their tags, as supplied with [Require].
+	requiredKeys map[string]struct{}
+
+	// requiredIf is the key of the environment variable that conditionally
+	// makes this field required.
+	requiredIf string
+
+	// fileFallback causes the value of a field whose key is not set to be read
+	// from the file named by the key with a `_FILE` suffix, if that is set.
+	fileFallback bool
+
+	// concat are the keys of the environment variables whose values are
+	// appended to the value of this field, in order.
+	concat []string
+
+	// join is the string inserted between the values of the concatenated keys.
+	join string
+
+	// unsetKey is the first of the concatenated keys that is not set, if any.
+	unsetKey string
+
+	// inline is the format that a nested struct is decoded from, when it is
+	// decoded from the value of a single key.
+	inline string
+
+	// presence causes bool values to be decoded from whether the key is set,
+	// regardless of its value.
+	presence bool
+
+	// csv causes slice and map values to be split as a single CSV record, so
+	// that quoted entries may contain the separator.
+	csv bool
+
+	// text causes values to be decoded through the encoding.TextUnmarshaler
+	// implementation of their type, ahead of any other handling.
+	text bool
+
+	// unique causes duplicate elements of slice values to be removed, keeping
+	// the first occurrence of each.
+	unique bool
+
+	// escape causes slice values to be split only on separators that are not
+	// escaped with a backslash.
+	escape bool
+
+	// nonEmpty causes a required field that is set to an empty value to be
+	// treated as if it were not set.
+	nonEmpty bool
+
+	// group is the name of the mutually-exclusive group this field belongs to.
+	group string
+
+	trim       bool
+	trimPrefix string
+	trimSuffix string
+
+	// secret causes the value to be masked in errors and generated output.
+	secret bool
+
+	// omitEmpty causes empty values to be omitted when marshaling.
+	omitEmpty bool
+
+	// verboseErrors causes the values of non-secret fields to be included in
+	// the messages of parse errors.
+	verboseErrors bool
+
+	// casing normalizes the case of string values, if set.
+	casing func(string) string
+
+	// oneOf is the set of values a string value is allowed to have, if set.
+	oneOf []string
+
+	// location is the location that time values without zone information are
+	// interpreted in. This is UTC if unset.
+	location *time.Location
+
+	// byteSize causes integer values to be parsed as human-readable sizes, such
+	// as "10MB", in bytes.
+	byteSize bool
+
+	// percent causes float values to be parsed as percentages, such as "75%",
+	// in fractions of one.
+	percent bool
+
+	// base is the base that integer values are parsed in. This is 0 by default,
+	// which infers the base from the prefix of the value.
+	base int
+
+	// unit is the unit that durations given as bare integers are multiplied
+	// by. This is 0 by default, which requires durations to include a unit.
+	unit time.Duration
+
+	// min and max are the inclusive bounds of a numeric value, if set.
+	min *float64
+	max *float64
+
+	// minLen and maxLen are the inclusive bounds of the number of elements of
+	// a slice or map value, if set.
+	minLen *int
+	maxLen *int
+
+	// unused receives the keys of the environment that were not consumed while
+	// unmarshaling, if set.
+	unused *[]string
+
+	// disallowUnknown causes keys of the environment that were not consumed
+	// while unmarshaling to be reported as an error.
+	disallowUnknown bool
+
+	// decoders are the decoders for custom tag options, keyed by the name of
+	// the option, as supplied with [WithTagDecoder].
+	decoders map[string]TagDecoder
+
+	// decode is the decoder for the custom tag option of the field, if any.
+	decode TagDecoder
+
+	// prefix is prepended to every key read from a struct, and is used for
+	// decoding nested structs.
+	prefix string
+
+	// embedding is the chain of struct types that embed the struct being
+	// visited, outermost first.
+	embedding []reflect.Type
+
+	// parseBool parses the value of bool fields.
+	parseBool func(string) (bool, error)
+
+	// ctx is checked for cancellation before each field is decoded, if set.
+	ctx context.Context
+
+	// lookup replaces the default source of environment variables, if set.
+	lookup lookup
+
+	// lookupContext replaces the default source of environment variables with
+	// one that is given ctx, if set.
+	lookupContext func(ctx context.Context, key string) (string, bool, error)
+
+	// lookupErr is the first error returned from lookupContext, if any.
+	lookupErr error
+
+	// tracer is called with the key, presence, and safe value of each field as
+	// it is resolved, if set.
+	tracer func(key string, found bool, value string)
+
+	// report receives the status of each field as it is resolved, if set.
+	report Report
+
+	// nameFunc derives the keys of untagged fields from their names, if set.
+	nameFunc func(string) string
+
+	// keys enumerates the keys of the environment being decoded. This is nil
+	// when the source is unable to enumerate its keys.
+	keys keyLister
+}
+
+// newTagOptions creates the default tag options, with the given options
+// applied.
+func newTagOptions(opts ...UnmarshalOption) *tagOptions {
+	tagOptions := &tagOptions{
+		required:  false,
+		sep:       ",",
+		kvsep:     "=",
+		parseBool: strconv.ParseBool,
+	}
+	for _, opt := range opts {
+		opt.apply(tagOptions)
+	}
+	return tagOptions
+}
+
+// lookupOr returns the lookup supplied with [WithLookup] or
+// [WithLookupContext], or fallback if none was supplied.
+//
+// Once a lookup supplied with [WithLookupContext] has failed, it is not called
+// again, and every key is treated as unset. The failure is reported by
+// lookupFailed.
+func (t *tagOptions) lookupOr(fallback lookup) lookup {
+	if t.lookupContext != nil {
+		ctx := t.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		return func(key string) (string, bool) {
+			if t.lookupErr != nil {
+				return "", false
+			}
+			value, ok, err := t.lookupContext(ctx, key)
+			if err != nil {
+				t.lookupErr = fmt.Errorf("env: lookup of '%s' failed: %w", key, err)
+				return "", false
+			}
+			return value, ok
+		}
+	}
+	if t.lookup != nil {
+		return t.lookup
+	}
+	return fallback
+}
+
+// lookupFailed returns the error from the lookup supplied with
+// [WithLookupContext] if it has failed, since any error from decoding is only
+// a consequence of it. Otherwise err is returned.
+func (t *tagOptions) lookupFailed(err error) error {
+	if t.lookupErr != nil {
+		return t.lookupErr
+	}
+	return err
+}
+
+// enabled returns true if the field is not gated behind a feature, or if the
+// feature it is gated behind has been enabled.
+func (t *tagOptions) enabled() bool {
+	if t.feature == "" {
+		return true
+	}
+	_, ok := t.features[t.feature]
+	return ok
+}
+
+// split splits a slice or map value into its entries on the separator. If the
+// csv option is set, the value is instead read as a single CSV record that uses
+// the separator as its delimiter, so that quoted entries may contain it.
+func (t *tagOptions) split(value string) ([]string, error) {
+	if !t.csv {
+		if t.escape && t.sep != "" {
+			return splitEscaped(value, t.sep), nil
+		}
+		return strings.Split(value, t.sep), nil
+	}
+	comma, size := utf8.DecodeRuneInString(t.sep)
+	if size == 0 || size != len(t.sep) {
+		return nil, fmt.Errorf("csv separator %q must be a single character", t.sep)
+	}
+	reader := csv.NewReader(strings.NewReader(value))
+	reader.Comma = comma
+	record, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		return nil, fmt.Errorf("expected a single csv record")
+	}
+	return record, nil
+}
+
+// elemTag returns a copy of the options for decoding the given entry of a slice
+// or map value. Requirements apply to the variable as a whole, which is already
+// known to be set, so they are not checked again for each entry.
+func (t *tagOptions) elemTag(entry string) *tagOptions {
+	elem := *t
+	elem.value = entry
+	elem.required = false
+	elem.nonEmpty = false
+	return &elem
 }
 
-func toScreamingSnake(s string) string {
-	var builder strings.Builder
-	prevLower := false
-	for _, r := range s {
-		if prevLower && unicode.IsUpper(r) {
-			builder.WriteByte('_')
+// splitEscaped splits the value on each separator that is not preceded by a
+// backslash. Escaped separators and backslashes are unescaped, and any other
+// backslash is kept as-is.
+func splitEscaped(value, sep string) []string {
+	var parts []string
+	var sb strings.Builder
+	for i := 0; i < len(value); {
+		switch {
+		case value[i] == '\\' && strings.HasPrefix(value[i+1:], sep):
+			sb.WriteString(sep)
+			i += 1 + len(sep)
+		case value[i] == '\\' && strings.HasPrefix(value[i+1:], `\`):
+			sb.WriteByte('\\')
+			i += 2
+		case strings.HasPrefix(value[i:], sep):
+			parts = append(parts, sb.String())
+			sb.Reset()
+			i += len(sep)
+		default:
+			sb.WriteByte(value[i])
+			i++
+		}
+	}
+	return append(parts, sb.String())
+}
+
+// fieldKey returns the environment variable key of the field, without any
+// prefix. Keys derived from the field name use the naming strategy supplied
+// with [NameFunc], if any.
+func (t *tagOptions) fieldKey(field *reflect.StructField, fieldTag *fieldTag) string {
+	if fieldTag.derived && t.nameFunc != nil {
+		return t.nameFunc(field.Name)
+	}
+	return fieldTag.key
+}
+
+// keysWithPrefix returns the keys of the environment that start with the
+// given prefix, sorted in ascending order. The result is always empty if the
+// environment is unable to enumerate its keys.
+func (t *tagOptions) keysWithPrefix(prefix string) []string {
+	if t.keys == nil {
+		return nil
+	}
+	var result []string
+	for _, key := range t.keys.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// parseError wraps the error in a [ParseError] for the value. Occurrences of
+// the value in the error's message are masked, unless the [VerboseErrors]
+// option is used on a field that is not a secret.
+func (t *tagOptions) parseError(rt reflect.Type, err error) error {
+	if t.secret || !t.verboseErrors {
+		err = &redactedError{
+			err:    err,
+			value:  t.value,
+			secret: t.secret,
+		}
+	}
+	return &ParseError{
+		Key:   t.key,
+		Value: t.safeValue(t.value),
+		Type:  rt,
+		Err:   err,
+	}
+}
+
+// missing returns true if the field is not set, or if it is set to an empty
+// value and the nonempty option has been applied.
+func (t *tagOptions) missing() bool {
+	return t.missingKey() != ""
+}
+
+// missingKey returns the key that does not satisfy the requirement of the
+// field, or an empty string if the requirement is satisfied. This is the key of
+// the field itself, unless only one of its concatenated keys is not set.
+func (t *tagOptions) missingKey() string {
+	if !t.set || (t.nonEmpty && t.value == "") {
+		return t.key
+	}
+	return t.unsetKey
+}
+
+// readFile reads the value of the field from the file named by its `_FILE`
+// key, if that key is set. A single trailing newline is trimmed from the
+// contents of the file.
+func (t *tagOptions) readFile(lookup lookup, rt reflect.Type) error {
+	fileKey := t.key + "_FILE"
+	path, ok := lookup(fileKey)
+	if !ok {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return &ParseError{
+			Key:   fileKey,
+			Value: path,
+			Type:  rt,
+			Err:   err,
 		}
-		prevLower = unicode.IsLower(r)
-		builder.WriteRune(r)
 	}
-	return strings.ToUpper(builder.String())
+	value := string(data)
+	if trimmed, ok := strings.CutSuffix(value, "\n"); ok {
+		value = strings.TrimSuffix(trimmed, "\r")
+	}
+	t.value, t.set = value, true
+	return nil
 }
 
-func readTag(lookup lookup, field *reflect.StructField, opts ...UnmarshalOption) (*tagOptions, error) {
+// concatenate appends the values of the `concat` keys to the value, in order.
+// Keys that are not set are treated as empty, and the field is set if any of
+// its keys are set.
+func (t *tagOptions) concatenate(lookup lookup) {
+	if !t.set {
+		t.unsetKey = t.key
+	}
+	values := []string{t.value}
+	for _, key := range t.concat {
+		value, ok := lookup(key)
+		if !ok && t.unsetKey == "" {
+			t.unsetKey = key
+		}
+		t.set = t.set || ok
+		values = append(values, value)
+	}
+	t.value = strings.Join(values, t.join)
+}
+
+// safeValue returns the value if it may be safely displayed, or a redacted
+// placeholder if the field is a secret.
+func (t *tagOptions) safeValue(value string) string {
+	if t.secret {
+		return string(redacted)
+	}
+	return value
+}
+
+// transform returns the tag options with the trimming options applied to the
+// value. The receiver is returned unchanged if no trimming options are set.
+func (t *tagOptions) transform() *tagOptions {
+	if !t.trim && t.trimPrefix == "" && t.trimSuffix == "" {
+		return t
+	}
+	result := *t
+	if result.trim {
+		result.value = strings.TrimSpace(result.value)
+	}
+	result.value = strings.TrimPrefix(result.value, result.trimPrefix)
+	result.value = strings.TrimSuffix(result.value, result.trimSuffix)
+	return &result
+}
+
+// parseTag splits the `env` tag of the field into the environment variable key
+// and the remaining tag options. If no tag is present, the key is derived from
+// the field name.
+func parseTag(field *reflect.StructField) (string, []string) {
 	tag, ok := field.Tag.Lookup("env")
 	if !ok {
-		tag = toScreamingSnake(field.Name)
+		tag = ScreamingSnake(field.Name)
 	}
 
 	parts := strings.Split(tag, ",")
-	key := parts[0]
+	return parts[0], parts[1:]
+}
 
-	value, ok := lookup(key)
-	tagOptions := &tagOptions{
-		key:      key,
-		value:    value,
-		set:      ok,
-		required: false,
-		sep:      ",",
+// readTag applies the parsed tag of the field to a copy of the base options,
+// and looks up its value.
+func readTag(lookup lookup, base *tagOptions, field *reflect.StructField, fieldTag *fieldTag) (*tagOptions, error) {
+	tagOptions, err := applyFieldTag(base, field, fieldTag)
+	if err != nil {
+		return nil, err
 	}
-	for _, opt := range opts {
-		opt.apply(tagOptions)
+
+	// Fields gated behind a disabled feature are never looked up.
+	if !tagOptions.enabled() {
+		return tagOptions, nil
+	}
+	if _, ok := tagOptions.requiredKeys[tagOptions.key]; ok {
+		tagOptions.required = true
+	}
+	tagOptions.value, tagOptions.set = lookup(tagOptions.key)
+	if !tagOptions.set && tagOptions.fileFallback {
+		if err := tagOptions.readFile(lookup, field.Type); err != nil {
+			return nil, err
+		}
+	}
+	if len(tagOptions.concat) > 0 {
+		tagOptions.concatenate(lookup)
+	}
+
+	// Conditions are resolved against the environment itself rather than the
+	// decoded fields, so they do not depend on the order fields are declared in.
+	if tagOptions.requiredIf != "" {
+		if tagOptions.required || !isTruthy(lookup(tagOptions.requiredIf)) {
+			tagOptions.requiredIf = ""
+		} else {
+			tagOptions.required = true
+		}
+	}
+	return tagOptions, nil
+}
+
+// applyFieldTag applies the parsed tag of the field to a copy of the base
+// options.
+func applyFieldTag(base *tagOptions, field *reflect.StructField, fieldTag *fieldTag) (*tagOptions, error) {
+	if fieldTag.invalid != "" {
+		return nil, &InvalidTagOptionError{
+			Key:    fieldTag.key,
+			Option: fieldTag.invalid,
+			Type:   field.Type,
+			Field:  field,
+		}
+	}
+
+	tagOptions := *base
+	tagOptions.key = tagOptions.prefix + base.fieldKey(field, fieldTag)
+	for _, option := range fieldTag.options {
+		option(&tagOptions)
+	}
+	for _, option := range fieldTag.custom {
+		decode, ok := base.decoders[option]
+		if !ok {
+			return nil, &InvalidTagOptionError{
+				Key:    fieldTag.key,
+				Option: option,
+				Type:   field.Type,
+				Field:  field,
+			}
+		}
+		tagOptions.decode = decode
+	}
+	return &tagOptions, nil
+}
+
+// fieldTag is the parsed form of the `env` tag of a field.
+type fieldTag struct {
+	// key is the environment variable key, without any prefix.
+	key string
+
+	// derived is true if the key was derived from the field name, rather than
+	// specified by the tag.
+	derived bool
+
+	// options are applied to the tag options, in order, after the options
+	// used for decoding.
+	options []apply
+
+	// invalid is the first invalid tag option of the field, if any.
+	invalid string
+
+	// custom are the tag options that are not built in, which must name a
+	// decoder supplied with [WithTagDecoder].
+	custom []string
+}
+
+// structField is a field of a struct along with its parsed tag.
+type structField struct {
+	field reflect.StructField
+	tag   *fieldTag
+}
+
+// structFields caches the fields of each struct type that has been decoded,
+// keyed by [reflect.Type]. Since parsed tags do not depend on the options used
+// for decoding, they are computed once per type and reused, similar to how
+// encoding/json caches its struct information.
+var structFields sync.Map
+
+// cachedStructFields returns the exported fields of the struct type, along
+// with their parsed tags, computing them on first use. Unexported fields are
+// always ignored, even if they have an `env` tag, as are fields tagged
+// `env:"-"`. Untagged embedded structs are kept even if their type is
+// unexported, so that their exported fields are promoted, like encoding/json.
+func cachedStructFields(rt reflect.Type) []structField {
+	if cached, ok := structFields.Load(rt); ok {
+		return cached.([]structField)
+	}
+
+	fields := make([]structField, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if (!field.IsExported() && !isEmbeddedStruct(&field)) || isIgnored(&field) {
+			continue
+		}
+		fields = append(fields, structField{
+			field: field,
+			tag:   compileFieldTag(&field),
+		})
+	}
+	cached, _ := structFields.LoadOrStore(rt, fields)
+	return cached.([]structField)
+}
+
+// isIgnored returns true if the field is tagged `env:"-"`, which excludes it
+// from decoding and encoding entirely, like the "-" tag of encoding/json.
+func isIgnored(field *reflect.StructField) bool {
+	return field.Tag.Get("env") == "-"
+}
+
+// compileFieldTag parses the `env` tag of the field into a fieldTag.
+func compileFieldTag(field *reflect.StructField) *fieldTag {
+	key, parts := parseTag(field)
+
+	_, tagged := field.Tag.Lookup("env")
+	result := &fieldTag{
+		key:     key,
+		derived: !tagged,
+	}
+	invalid := func(option string) *fieldTag {
+		result.options = nil
+		result.invalid = option
+		return result
 	}
-	for _, part := range parts[1:] {
+	var min, max *float64
+	var minLen, maxLen *int
+	var required, presence, concat, csv, escape bool
+	var join *string
+	for _, part := range parts {
+		part := part
 		switch part {
 		case "required":
-			tagOptions.required = true
+			required = true
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.required = true
+			})
+		case "presence":
+			rt := field.Type
+			for rt.Kind() == reflect.Ptr {
+				rt = rt.Elem()
+			}
+			if rt.Kind() != reflect.Bool {
+				return invalid(part)
+			}
+			presence = true
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.presence = true
+			})
+		case "csv":
+			csv = true
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.csv = true
+			})
+		case "escape":
+			escape = true
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.escape = true
+			})
+		case "string":
+			if elem := elemType(field.Type); elem.Kind() != reflect.String && !reflect.PointerTo(elem).Implements(textUnmarshalerType) {
+				return invalid(part)
+			}
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.text = true
+			})
+		case "unique":
+			if !isUniqueSlice(field.Type) {
+				return invalid(part)
+			}
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.unique = true
+			})
+		case "nonempty":
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.nonEmpty = true
+			})
+		case "trim":
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.trim = true
+			})
+		case "secret":
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.secret = true
+			})
+		case "omitempty":
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.omitEmpty = true
+			})
+		case "lower", "upper":
+			if elemType(field.Type).Kind() != reflect.String {
+				return invalid(part)
+			}
+			casing := strings.ToLower
+			if part == "upper" {
+				casing = strings.ToUpper
+			}
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.casing = casing
+			})
+		case "bytes":
+			if !isInteger(elemType(field.Type)) {
+				return invalid(part)
+			}
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.byteSize = true
+			})
+		case "percent":
+			if !isFloat(elemType(field.Type)) {
+				return invalid(part)
+			}
+			result.options = append(result.options, func(tag *tagOptions) {
+				tag.percent = true
+			})
 		default:
 			if rest, ok := strings.CutPrefix(part, "sep="); ok {
-				tagOptions.sep = rest
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.sep = rest
+				})
 				continue
 			}
-			return nil, &InvalidTagOptionError{
-				Key:    key,
-				Option: part,
-				Type:   field.Type,
-				Field:  field,
+			if rest, ok := strings.CutPrefix(part, "kvsep="); ok && rest != "" {
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.kvsep = rest
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "feature="); ok && rest != "" {
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.feature = rest
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "requiredIf="); ok && rest != "" {
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.requiredIf = tag.prefix + rest
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "concat="); ok && rest != "" {
+				concat = true
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.concat = append(tag.concat[:len(tag.concat):len(tag.concat)], tag.prefix+rest)
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "join="); ok {
+				join = &part
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.join = rest
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "group="); ok && rest != "" {
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.group = rest
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "trimprefix="); ok {
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.trimPrefix = rest
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "trimsuffix="); ok {
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.trimSuffix = rest
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "oneof="); ok {
+				if elemType(field.Type).Kind() != reflect.String || strings.TrimSpace(rest) == "" {
+					return invalid(part)
+				}
+				oneOf := strings.Fields(rest)
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.oneOf = oneOf
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "inline="); ok {
+				if _, ok := inlineParsers[rest]; !ok || !isInlineStruct(field.Type) {
+					return invalid(part)
+				}
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.inline = rest
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "tz="); ok {
+				location, err := time.LoadLocation(rest)
+				if err != nil || rest == "" || elemType(field.Type) != timeType {
+					return invalid(part)
+				}
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.location = location
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "unit="); ok {
+				unit, ok := durationUnits[rest]
+				if !ok || elemType(field.Type) != durationType {
+					return invalid(part)
+				}
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.unit = unit
+				})
+				continue
+			}
+			if rest, ok := strings.CutPrefix(part, "base="); ok {
+				base, err := strconv.Atoi(rest)
+				if err != nil || base < 2 || base > 36 || !isInteger(elemType(field.Type)) {
+					return invalid(part)
+				}
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.base = base
+				})
+				continue
+			}
+			if bound, ok := cutLength(part, "minlen="); ok {
+				if !isCollection(field.Type) || bound == nil {
+					return invalid(part)
+				}
+				minLen = bound
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.minLen = bound
+				})
+				continue
+			}
+			if bound, ok := cutLength(part, "maxlen="); ok {
+				if !isCollection(field.Type) || bound == nil {
+					return invalid(part)
+				}
+				maxLen = bound
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.maxLen = bound
+				})
+				continue
+			}
+			if bound, ok := cutBound(part, "min="); ok {
+				if !isNumeric(elemType(field.Type)) || bound == nil {
+					return invalid(part)
+				}
+				min = bound
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.min = bound
+				})
+				continue
+			}
+			if bound, ok := cutBound(part, "max="); ok {
+				if !isNumeric(elemType(field.Type)) || bound == nil {
+					return invalid(part)
+				}
+				max = bound
+				result.options = append(result.options, func(tag *tagOptions) {
+					tag.max = bound
+				})
+				continue
+			}
+			if part != "" && !strings.Contains(part, "=") {
+				result.custom = append(result.custom, part)
+				continue
 			}
+			return invalid(part)
 		}
 	}
-	return tagOptions, nil
+	if min != nil && max != nil && *min > *max {
+		return invalid(fmt.Sprintf("min=%v", *min))
+	}
+	if minLen != nil && maxLen != nil && *minLen > *maxLen {
+		return invalid(fmt.Sprintf("minlen=%v", *minLen))
+	}
+	if required && presence {
+		return invalid("presence")
+	}
+	if csv && escape {
+		return invalid("escape")
+	}
+	if join != nil && !concat {
+		return invalid(*join)
+	}
+	return result
+}
+
+// parseFileMode parses a file mode from its octal permission bits, such as
+// "0644". The base is always octal, regardless of any leading zero, since that
+// is the universal convention for file modes; an optional "0o" prefix is also
+// accepted. Only the permission bits of [os.ModePerm] may be set.
+func parseFileMode(value string) (os.FileMode, error) {
+	digits := value
+	if rest, ok := strings.CutPrefix(digits, "0o"); ok {
+		digits = rest
+	} else if rest, ok := strings.CutPrefix(digits, "0O"); ok {
+		digits = rest
+	}
+	mode, err := strconv.ParseUint(digits, 8, 32)
+	if err != nil {
+		return 0, err
+	}
+	if mode&^uint64(os.ModePerm) != 0 {
+		return 0, fmt.Errorf("file mode %q has bits outside of the permission bits %#o", value, os.ModePerm)
+	}
+	return os.FileMode(mode), nil
+}
+
+// isTruthy returns true if the looked-up value is set, non-empty, and is not a
+// boolean false value (such as "0" or "false").
+func isTruthy(value string, ok bool) bool {
+	if !ok || value == "" {
+		return false
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	return true
 }
 
 func bitness(rt reflect.Type) int {
@@ -147,53 +1038,404 @@ func bitness(rt reflect.Type) int {
 		return 16
 	case reflect.Int32, reflect.Uint32, reflect.Float32:
 		return 32
-	case reflect.Int64, reflect.Uint64, reflect.Float64:
+	case reflect.Int64, reflect.Uint64, reflect.Float64, reflect.Complex64:
 		return 64
+	case reflect.Complex128:
+		return 128
 	case reflect.Int, reflect.Uint:
 		return 0
 	default:
 		return rt.Bits()
 	}
-}
+}
+
+func decode(lookup lookup, rv reflect.Value, opts ...UnmarshalOption) error {
+	rt := rv.Type()
+	if rt.Kind() != reflect.Ptr {
+		return fmt.Errorf("env: expected pointer, got '%s'", rt.String())
+	}
+
+	for rt.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return fmt.Errorf("env: cannot unmarshal into nil pointer")
+		}
+		rv = rv.Elem()
+		rt = rt.Elem()
+	}
+	return decodeStruct(lookup, rv, rt, opts...)
+}
+
+// decodeStruct decodes the fields of the struct, and then validates it if it
+// implements [Validator].
+func decodeStruct(lookup lookup, rv reflect.Value, rt reflect.Type, opts ...UnmarshalOption) error {
+	if err := decodeFields(lookup, rv, rt, opts...); err != nil {
+		return err
+	}
+	prefix := newTagOptions(opts...).prefix
+	return validate(strings.TrimSuffix(prefix, "_"), rv, rt)
+}
+
+// decodeFields decodes the fields of the struct, without validating it.
+func decodeFields(lookup lookup, rv reflect.Value, rt reflect.Type, opts ...UnmarshalOption) error {
+	if rt.Kind() != reflect.Struct {
+		return &InvalidTypeError{
+			Type: rt,
+		}
+	}
+
+	base := newTagOptions(opts...)
+	var groups []*exclusiveGroup
+	fields := cachedStructFields(rt)
+	for i := range fields {
+		if base.ctx != nil {
+			if err := base.ctx.Err(); err != nil {
+				return fmt.Errorf("env: %w", err)
+			}
+		}
+		field := &fields[i].field
+		if isEmbeddedStruct(field) {
+			embeddedOpts, ok := base.enterEmbedded(rt, field, opts)
+			if !ok {
+				continue
+			}
+			if err := decodeEmbedded(lookup, base.prefix, field.Type, rv.FieldByIndex(field.Index), embeddedOpts...); err != nil {
+				return err
+			}
+			continue
+		}
+		tag, err := readTag(lookup, base, field, fields[i].tag)
+		if err != nil {
+			return err
+		}
+		if !tag.enabled() {
+			continue
+		}
+		if base.tracer != nil {
+			base.tracer(tag.key, tag.set, tag.safeValue(tag.value))
+		}
+		if tag.group != "" {
+			groups = addToGroup(groups, tag)
+		}
+
+		fv := rv.FieldByIndex(field.Index)
+		structSlice := tag.decode == nil && isStructSlice(field.Type)
+		if base.report != nil {
+			if structSlice {
+				base.report[tag.key] = tag.structSliceStatus(lookup, field.Type, fv, opts...)
+			} else {
+				base.report[tag.key] = tag.status(fv)
+			}
+		}
+		if tag.inline != "" {
+			if err := decodeInline(tag, field.Type, fv, opts...); err != nil {
+				return err
+			}
+			continue
+		}
+		if structSlice {
+			if err := decodeStructSlice(lookup, tag, field.Type, fv, opts...); err != nil {
+				return err
+			}
+			continue
+		}
+		if tag.decode != nil {
+			err = decodeCustom(tag, field.Type, fv)
+		} else {
+			err = decodeValue(lookup, tag, field.Name, field.Type, fv, field)
+		}
+		if err != nil {
+			return err
+		}
+		if tag.set {
+			if err := validate(tag.key, fv, field.Type); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, group := range groups {
+		if len(group.set) > 1 || (group.required && len(group.set) == 0) {
+			return &ExclusiveGroupError{
+				Group: group.name,
+				Keys:  group.set,
+			}
+		}
+	}
+	return nil
+}
+
+// decodeCustom decodes the value with the decoder of the field's custom tag
+// option. The field is only set once decoding succeeds.
+func decodeCustom(tag *tagOptions, rt reflect.Type, rv reflect.Value) error {
+	if !rv.CanSet() {
+		return fmt.Errorf("env: cannot set field for '%s'", tag.key)
+	}
+	if tag.required && tag.missing() {
+		return &RequirementError{
+			Key:       tag.missingKey(),
+			Type:      rt,
+			Condition: tag.requiredIf,
+		}
+	}
+	if !tag.set {
+		return nil
+	}
+
+	target := reflect.New(rt)
+	if err := tag.decode(tag.value, target.Interface()); err != nil {
+		return tag.parseError(rt, err)
+	}
+	rv.Set(target.Elem())
+	return nil
+}
+
+// validate calls the Validate method of the value if it implements
+// [Validator], and wraps any error it returns in a [ValidationError].
+func validate(key string, rv reflect.Value, rt reflect.Type) error {
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() {
+		rv = rv.Addr()
+	}
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil
+	}
+	validator, ok := rv.Interface().(Validator)
+	if !ok {
+		return nil
+	}
+	if err := validator.Validate(); err != nil {
+		return &ValidationError{
+			Key:  key,
+			Type: rt,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// isNestedStruct returns true if the type is a struct that is decoded field
+// by field, rather than from a single value.
+func isNestedStruct(rt reflect.Type) bool {
+	if rt.Kind() != reflect.Struct {
+		return false
+	}
+	switch rt {
+	case timeType, bigIntType, bigFloatType, regexpType:
+		return false
+	}
+	ptr := reflect.PointerTo(rt)
+	return !ptr.Implements(unmarshalerType) && !ptr.Implements(textUnmarshalerType) &&
+		!ptr.Implements(scannerType) && !ptr.Implements(sqlScannerType)
+}
+
+// isEmbeddedStruct returns true if the field is an untagged embedded struct, or
+// pointer to a struct, whose fields are decoded as if they were declared in the
+// embedding struct.
+func isEmbeddedStruct(field *reflect.StructField) bool {
+	if !field.Anonymous {
+		return false
+	}
+	if _, ok := field.Tag.Lookup("env"); ok {
+		return false
+	}
+	rt := field.Type
+	if rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return isNestedStruct(rt)
+}
+
+// decodeEmbedded decodes the fields of an embedded struct. Embedded pointers
+// are treated as optional sections: they are only allocated if at least one of
+// the struct's keys is set, and are otherwise left unchanged.
+func decodeEmbedded(lookup lookup, prefix string, rt reflect.Type, rv reflect.Value, opts ...UnmarshalOption) error {
+	if rt.Kind() != reflect.Ptr {
+		return decodeFields(lookup, rv, rt, opts...)
+	}
+
+	structType := rt.Elem()
+	keys := structKeys(structType, opts...)
+	if !anySet(lookup, prefix, keys) {
+		return nil
+	}
+	if !rv.IsNil() {
+		return decodeFields(lookup, rv.Elem(), structType, opts...)
+	}
+	if !rv.CanSet() {
+		return fmt.Errorf("env: cannot set embedded pointer to unexported struct type %s", structType)
+	}
+	elem := reflect.New(structType)
+	if err := decodeFields(lookup, elem.Elem(), structType, opts...); err != nil {
+		return err
+	}
+	rv.Set(elem)
+	return nil
+}
+
+// isStructSlice returns true if the type is a slice of nested structs, or
+// pointers to nested structs.
+func isStructSlice(rt reflect.Type) bool {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Slice {
+		return false
+	}
+	rt = rt.Elem()
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return isNestedStruct(rt)
+}
+
+// decodeStructSlice decodes a slice of nested structs from keys indexed by a
+// numeric suffix of the tag key, such as `UPSTREAM_0_HOST` and
+// `UPSTREAM_1_HOST`. Decoding stops at the first index for which none of the
+// struct's keys are set.
+func decodeStructSlice(lookup lookup, tag *tagOptions, rt reflect.Type, rv reflect.Value, opts ...UnmarshalOption) error {
+	if !rv.CanSet() {
+		return fmt.Errorf("env: cannot set field for '%s'", tag.key)
+	}
+
+	sliceType := rt
+	for sliceType.Kind() == reflect.Ptr {
+		sliceType = sliceType.Elem()
+	}
+	elemType := sliceType.Elem()
+	structType := elemType
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	keys := structKeys(structType, opts...)
 
-func decode(lookup lookup, rv reflect.Value, opts ...UnmarshalOption) error {
-	rt := rv.Type()
-	if rt.Kind() != reflect.Ptr {
-		return fmt.Errorf("env: expected pointer, got '%s'", rt.String())
+	slice := reflect.MakeSlice(sliceType, 0, 0)
+	for i := 0; ; i++ {
+		prefix := fmt.Sprintf("%s_%d_", tag.key, i)
+		if !anySet(lookup, prefix, keys) {
+			break
+		}
+
+		elem := reflect.New(elemType).Elem()
+		structValue, _ := deref(elem, elemType)
+		elemOpts := append(opts[:len(opts):len(opts)], withPrefix(prefix))
+		if err := decodeStruct(lookup, structValue, structType, elemOpts...); err != nil {
+			return err
+		}
+		slice = reflect.Append(slice, elem)
 	}
 
-	for rt.Kind() == reflect.Ptr {
-		if rv.IsNil() {
-			return fmt.Errorf("env: cannot unmarshal into nil pointer")
+	if slice.Len() == 0 {
+		if tag.required {
+			return &RequirementError{
+				Key:       tag.key,
+				Type:      rt,
+				Condition: tag.requiredIf,
+			}
 		}
-		rv = rv.Elem()
-		rt = rt.Elem()
+		return nil
 	}
-	return decodeStruct(lookup, rv, rt, opts...)
+	if err := tag.checkLength(rt, slice.Len()); err != nil {
+		return err
+	}
+	rv, _ = deref(rv, rt)
+	rv.Set(slice)
+	return nil
 }
 
-func decodeStruct(lookup lookup, rv reflect.Value, rt reflect.Type, opts ...UnmarshalOption) error {
-	if rt.Kind() != reflect.Struct {
-		return &InvalidTypeError{
-			Type: rt,
+// anySet returns true if any of the keys is set with the given prefix.
+func anySet(lookup lookup, prefix string, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := lookup(prefix + key); ok {
+			return true
 		}
 	}
+	return false
+}
 
-	length := rt.NumField()
-	for i := 0; i < length; i++ {
-		field := rt.Field(i)
-		tag, err := readTag(lookup, &field, opts...)
-		if err != nil {
-			return err
+// withPrefix returns an [UnmarshalOption] that prefixes every key read from a
+// struct. The struct is not embedded in the one that contains it, so this also
+// starts a new chain of embedding types.
+func withPrefix(prefix string) UnmarshalOption {
+	return apply(func(tag *tagOptions) {
+		tag.prefix = prefix
+		tag.embedding = nil
+	})
+}
+
+// withContext returns the options with an additional option that causes
+// decoding to be aborted when ctx is cancelled.
+func withContext(ctx context.Context, opts []UnmarshalOption) []UnmarshalOption {
+	return append(opts[:len(opts):len(opts)], apply(func(tag *tagOptions) {
+		tag.ctx = ctx
+	}))
+}
+
+// enterEmbedded returns the options for visiting the embedded struct field of
+// the struct type rt. False is returned if the embedded type is already being
+// visited, such as when a type embeds a pointer to itself. As with
+// encoding/json, every field of such a type is shadowed by a field of the type
+// that embeds it, so it is skipped rather than visited without end.
+func (t *tagOptions) enterEmbedded(rt reflect.Type, field *reflect.StructField, opts []UnmarshalOption) ([]UnmarshalOption, bool) {
+	embedded := field.Type
+	for embedded.Kind() == reflect.Ptr {
+		embedded = embedded.Elem()
+	}
+	if embedded == rt {
+		return nil, false
+	}
+	for _, visited := range t.embedding {
+		if visited == embedded {
+			return nil, false
 		}
+	}
+	embedding := append(t.embedding[:len(t.embedding):len(t.embedding)], rt)
+	return append(opts[:len(opts):len(opts)], apply(func(tag *tagOptions) {
+		tag.embedding = embedding
+	})), true
+}
 
-		if err := decodeValue(lookup, tag, field.Name, field.Type, rv.Field(i), &field); err != nil {
-			return err
+// withKeys returns the options with an additional option that allows decoding
+// to enumerate the keys of the environment through keys.
+func withKeys(keys keyLister, opts []UnmarshalOption) []UnmarshalOption {
+	return append(opts[:len(opts):len(opts)], apply(func(tag *tagOptions) {
+		tag.keys = keys
+	}))
+}
+
+// exclusiveGroup tracks the members of a mutually-exclusive field group.
+type exclusiveGroup struct {
+	name     string
+	required bool
+	set      []string
+}
+
+// addToGroup records the field described by the tag into its exclusive group,
+// creating the group if it does not exist yet.
+//
+// Since requirements apply to the group as a whole rather than any individual
+// member, this clears the required flag of the tag.
+func addToGroup(groups []*exclusiveGroup, tag *tagOptions) []*exclusiveGroup {
+	var group *exclusiveGroup
+	for _, g := range groups {
+		if g.name == tag.group {
+			group = g
+			break
 		}
 	}
-	return nil
+	if group == nil {
+		group = &exclusiveGroup{name: tag.group}
+		groups = append(groups, group)
+	}
+
+	group.required = group.required || tag.required
+	tag.required = false
+	if tag.set {
+		group.set = append(group.set, tag.key)
+	}
+	return groups
 }
 
+// timeLayouts are the layouts that are tried, in order, if a time value is
+// not in the RFC 3339 format.
 var timeLayouts = []string{
 	time.Layout,
 	time.ANSIC,
@@ -204,8 +1446,6 @@ var timeLayouts = []string{
 	time.RFC850,
 	time.RFC1123,
 	time.RFC1123Z,
-	time.RFC3339,
-	time.RFC3339Nano,
 	time.Stamp,
 	time.StampMilli,
 	time.StampMicro,
@@ -216,11 +1456,266 @@ var timeLayouts = []string{
 	time.Kitchen,
 }
 
-func pointsToStruct(rt reflect.Type) bool {
+// parseTime parses the value as a time, returning the first successful parse.
+//
+// RFC 3339 is by far the most common format, so it is attempted before any of
+// the other [timeLayouts]. Since fractional seconds are accepted when parsing
+// with [time.RFC3339], this also covers [time.RFC3339Nano].
+//
+// Values in layouts without zone information are interpreted in the given
+// location, or in UTC if it is nil.
+func parseTime(value string, loc *time.Location) (time.Time, error) {
+	parse := time.Parse
+	if loc != nil {
+		parse = func(layout, value string) (time.Time, error) {
+			return time.ParseInLocation(layout, value, loc)
+		}
+	}
+	result, err := parse(time.RFC3339, value)
+	if err == nil {
+		return result, nil
+	}
+	for _, layout := range timeLayouts {
+		if result, err = parse(layout, value); err == nil {
+			return result, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// cutLength parses a length bound from a tag option with the given prefix.
+// The returned bound is nil if the option has the prefix but is not a
+// non-negative integer.
+func cutLength(part, prefix string) (*int, bool) {
+	rest, ok := strings.CutPrefix(part, prefix)
+	if !ok {
+		return nil, false
+	}
+	bound, err := strconv.Atoi(rest)
+	if err != nil || bound < 0 {
+		return nil, true
+	}
+	return &bound, true
+}
+
+// isCollection returns true if the type is a slice or map, or a pointer to
+// one.
+func isCollection(rt reflect.Type) bool {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return rt.Kind() == reflect.Slice || rt.Kind() == reflect.Map
+}
+
+// isUniqueSlice returns true if the type is a slice, or a pointer to one,
+// whose elements may be deduplicated with the `unique` option. Pointer elements
+// are compared by the values they point to.
+func isUniqueSlice(rt reflect.Type) bool {
 	for rt.Kind() == reflect.Ptr {
 		rt = rt.Elem()
 	}
-	return rt.Kind() == reflect.Struct
+	if rt.Kind() != reflect.Slice {
+		return false
+	}
+	elem := rt.Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Comparable() && elem.Kind() != reflect.Interface
+}
+
+// uniqueElems returns a slice containing the elements of the slice with any
+// duplicates removed, preserving the order in which they were first seen.
+func uniqueElems(slice reflect.Value) reflect.Value {
+	seen := make(map[any]struct{}, slice.Len())
+	result := reflect.MakeSlice(slice.Type(), 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+		key := elem
+		for key.Kind() == reflect.Ptr {
+			key = key.Elem()
+		}
+		if _, ok := seen[key.Interface()]; ok {
+			continue
+		}
+		seen[key.Interface()] = struct{}{}
+		result = reflect.Append(result, elem)
+	}
+	return result
+}
+
+// cutBound parses a numeric bound from a tag option with the given prefix.
+// The returned bound is nil if the option has the prefix but is not a number.
+func cutBound(part, prefix string) (*float64, bool) {
+	rest, ok := strings.CutPrefix(part, prefix)
+	if !ok {
+		return nil, false
+	}
+	bound, err := strconv.ParseFloat(rest, 64)
+	if err != nil {
+		return nil, true
+	}
+	return &bound, true
+}
+
+// checkRange returns a [ValidationError] if the value falls outside of the
+// bounds set by the `min` and `max` tag options.
+func (t *tagOptions) checkRange(rt reflect.Type, value float64) error {
+	var err error
+	if t.min != nil && value < *t.min {
+		err = fmt.Errorf("must be at least %v", *t.min)
+	} else if t.max != nil && value > *t.max {
+		err = fmt.Errorf("must be at most %v", *t.max)
+	}
+	if err != nil {
+		return &ValidationError{
+			Key:   t.key,
+			Value: t.safeValue(t.value),
+			Type:  rt,
+			Err:   err,
+		}
+	}
+	return nil
+}
+
+// checkLength returns a [ValidationError] if the number of elements of a slice
+// or map falls outside of the bounds set by the `minlen` and `maxlen` tag
+// options.
+func (t *tagOptions) checkLength(rt reflect.Type, length int) error {
+	var err error
+	if t.minLen != nil && length < *t.minLen {
+		err = fmt.Errorf("must have at least %d elements, got %d", *t.minLen, length)
+	} else if t.maxLen != nil && length > *t.maxLen {
+		err = fmt.Errorf("must have at most %d elements, got %d", *t.maxLen, length)
+	}
+	if err != nil {
+		return &ValidationError{
+			Key:   t.key,
+			Value: t.safeValue(t.value),
+			Type:  rt,
+			Err:   err,
+		}
+	}
+	return nil
+}
+
+// parseInt parses the value as a signed integer of the given type, honoring the
+// `base` and `bytes` options.
+func (t *tagOptions) parseInt(rt reflect.Type) (int64, error) {
+	if !t.byteSize {
+		return strconv.ParseInt(t.value, t.base, bitness(rt))
+	}
+	size, err := parseByteSize(t.value)
+	if err != nil {
+		return 0, err
+	}
+	if reflect.Zero(rt).OverflowInt(size) {
+		return 0, fmt.Errorf("size %q overflows %s", t.value, rt)
+	}
+	return size, nil
+}
+
+// parseUint parses the value as an unsigned integer of the given type,
+// honoring the `base` and `bytes` options.
+func (t *tagOptions) parseUint(rt reflect.Type) (uint64, error) {
+	if !t.byteSize {
+		return strconv.ParseUint(t.value, t.base, bitness(rt))
+	}
+	size, err := parseByteSize(t.value)
+	if err != nil {
+		return 0, err
+	}
+	if reflect.Zero(rt).OverflowUint(uint64(size)) {
+		return 0, fmt.Errorf("size %q overflows %s", t.value, rt)
+	}
+	return uint64(size), nil
+}
+
+// parseFloat parses the value as a float of the given type, honoring the
+// `percent` option.
+func (t *tagOptions) parseFloat(rt reflect.Type) (float64, error) {
+	if !t.percent {
+		return strconv.ParseFloat(t.value, bitness(rt))
+	}
+	return parsePercent(t.value, bitness(rt))
+}
+
+// durationUnits are the units accepted by the `unit` tag option.
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// parseDuration parses a duration with [time.ParseDuration]. If unit is
+// non-zero, a bare integer is also accepted, and is multiplied by the unit.
+func parseDuration(value string, unit time.Duration) (time.Duration, error) {
+	if unit == 0 {
+		return time.ParseDuration(value)
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.ParseDuration(value)
+	}
+	if n > math.MaxInt64/int64(unit) || n < math.MinInt64/int64(unit) {
+		return 0, fmt.Errorf("duration %q out of range", value)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// isInteger returns true if the type is decoded as a plain integer.
+func isInteger(rt reflect.Type) bool {
+	if rt == durationType {
+		return false
+	}
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isFloat returns true if the type is decoded as a floating-point number.
+func isFloat(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Float32 || rt.Kind() == reflect.Float64
+}
+
+// isNumeric returns true if the type is decoded as a plain number.
+func isNumeric(rt reflect.Type) bool {
+	if rt == durationType {
+		return false
+	}
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// elemType returns the innermost element type of the given type, looking
+// through any pointers and slices.
+func elemType(rt reflect.Type) reflect.Type {
+	for rt.Kind() == reflect.Ptr || rt.Kind() == reflect.Slice || rt.Kind() == reflect.Array {
+		rt = rt.Elem()
+	}
+	return rt
 }
 
 func deref(rv reflect.Value, rt reflect.Type) (reflect.Value, reflect.Type) {
@@ -239,117 +1734,274 @@ func decodeValue(lookup lookup, tag *tagOptions, name string, rt reflect.Type, r
 		return fmt.Errorf("env: cannot set field '%s'", name)
 	}
 
-	if !tag.set {
-		if tag.required {
-			return &RequirementError{
-				Key:  tag.key,
-				Type: rt,
-			}
-		}
+	// Presence flags are decoded from whether the key is set at all, and never
+	// from its value.
+	if tag.presence {
+		rv, _ = deref(rv, rt)
+		rv.SetBool(tag.set)
 		return nil
 	}
 
-	rv, rt = deref(rv, rt)
-
-	makeParseError := func(err error) error {
-		errParse := ParseError{
-			Key:   tag.key,
-			Value: tag.value,
-			Type:  rt,
-			Err:   err,
+	if tag.required && tag.missing() {
+		return &RequirementError{
+			Key:       tag.missingKey(),
+			Type:      rt,
+			Condition: tag.requiredIf,
 		}
-		return &errParse
+	}
+	if !tag.set {
+		return nil
 	}
 
-	// Try converting to Unmarshaler first
-	if marshaler, ok := rv.Addr().Interface().(Unmarshaler); ok {
-		if err := marshaler.UnmarshalEnv([]byte(tag.value)); err != nil {
-			return makeParseError(err)
+	// Pointers are decoded into a newly allocated value that is only assigned
+	// once decoding succeeds, so that a failed decode leaves nil pointers nil.
+	// Existing pointers are decoded through, preserving any default value.
+	if rt.Kind() == reflect.Ptr {
+		if !rv.IsNil() {
+			return decodeValue(lookup, tag, name, rt.Elem(), rv.Elem(), field)
+		}
+		elem := reflect.New(rt.Elem())
+		if err := decodeValue(lookup, tag, name, rt.Elem(), elem.Elem(), field); err != nil {
+			return err
 		}
+		rv.Set(elem)
+		return nil
 	}
 
-	// Fallback to TextUnmarshaler if it's available
-	if marshaler, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok {
-		if err := marshaler.UnmarshalText([]byte(tag.value)); err != nil {
-			return makeParseError(err)
+	// Slices, arrays, and maps are transformed per-element after being split
+	// instead.
+	if kind := rt.Kind(); kind != reflect.Slice && kind != reflect.Array && kind != reflect.Map {
+		tag = tag.transform()
+	}
+
+	makeParseError := func(err error) error {
+		return tag.parseError(rt, err)
+	}
+
+	// The `string` option forces the value through the text form of the type,
+	// ahead of the specific cases below and any Unmarshaler implementation.
+	if tag.text {
+		if marshaler, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := marshaler.UnmarshalText([]byte(tag.value)); err != nil {
+				return makeParseError(err)
+			}
+			return nil
 		}
 	}
 
-	// Handle specific cases
+	// Handle specific cases first, since some of these types also implement
+	// encoding.TextUnmarshaler with stricter formats.
 	switch rt {
 	case durationType:
-		duration, err := time.ParseDuration(tag.value)
+		duration, err := parseDuration(tag.value, tag.unit)
 		if err != nil {
 			return makeParseError(err)
 		}
 		rv.Set(reflect.ValueOf(duration))
 		return nil
+	case fileModeType:
+		mode, err := parseFileMode(tag.value)
+		if err != nil {
+			return makeParseError(err)
+		}
+		rv.SetUint(uint64(mode))
+		return nil
 	case timeType:
-		var err error
-		for _, layout := range timeLayouts {
-			var timeValue time.Time
-			timeValue, err = time.Parse(layout, tag.value)
-			if err != nil {
-				continue
-			}
-			rv.Set(reflect.ValueOf(timeValue))
-			err = nil
-			break
+		timeValue, err := parseTime(tag.value, tag.location)
+		if err != nil {
+			return makeParseError(err)
+		}
+		rv.Set(reflect.ValueOf(timeValue))
+		return nil
+	case bigIntType:
+		if _, ok := rv.Addr().Interface().(*big.Int).SetString(tag.value, 0); !ok {
+			return makeParseError(fmt.Errorf("invalid integer %q", tag.value))
+		}
+		return nil
+	case bigFloatType:
+		if _, ok := rv.Addr().Interface().(*big.Float).SetString(tag.value); !ok {
+			return makeParseError(fmt.Errorf("invalid float %q", tag.value))
 		}
+		return nil
+	case regexpType:
+		re, err := regexp.Compile(tag.value)
 		if err != nil {
 			return makeParseError(err)
 		}
+		rv.Set(reflect.ValueOf(re).Elem())
+		return nil
+	}
+
+	// Try converting to Unmarshaler first
+	if marshaler, ok := rv.Addr().Interface().(Unmarshaler); ok {
+		if err := marshaler.UnmarshalEnv([]byte(tag.value)); err != nil {
+			return makeParseError(err)
+		}
+		return nil
+	}
+
+	// Fallback to TextUnmarshaler if it's available. This must return before
+	// reaching the primitive kinds below, since types such as slog.Level are
+	// integers that are only meant to be decoded from their text form. Value
+	// only implements it for the sake of other encoders, and is decoded as a
+	// plain string so that string tag options still apply.
+	if marshaler, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok && rt != valueType {
+		if err := marshaler.UnmarshalText([]byte(tag.value)); err != nil {
+			return makeParseError(err)
+		}
 		return nil
 	}
 
 	// Handle decoding primitive types
 	switch rt.Kind() {
 	case reflect.String:
-		rv.SetString(tag.value)
+		value := tag.value
+		if tag.casing != nil {
+			value = tag.casing(value)
+		}
+		if tag.oneOf != nil && !contains(tag.oneOf, value) {
+			return &ValidationError{
+				Key:   tag.key,
+				Value: tag.safeValue(value),
+				Type:  rt,
+				Err:   fmt.Errorf("must be one of %s", strings.Join(tag.oneOf, ", ")),
+			}
+		}
+		rv.SetString(value)
 		return nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		integer, err := strconv.ParseInt(tag.value, 0, bitness(rt))
+		integer, err := tag.parseInt(rt)
 		if err != nil {
 			return makeParseError(err)
 		}
+		if err := tag.checkRange(rt, float64(integer)); err != nil {
+			return err
+		}
 		rv.SetInt(integer)
 		return nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		integer, err := strconv.ParseUint(tag.value, 0, bitness(rt))
+		integer, err := tag.parseUint(rt)
 		if err != nil {
 			return makeParseError(err)
 		}
+		if err := tag.checkRange(rt, float64(integer)); err != nil {
+			return err
+		}
 		rv.SetUint(integer)
 		return nil
 	case reflect.Float32, reflect.Float64:
-		value, err := strconv.ParseFloat(tag.value, bitness(rt))
+		value, err := tag.parseFloat(rt)
 		if err != nil {
 			return makeParseError(err)
 		}
+		if err := tag.checkRange(rt, value); err != nil {
+			return err
+		}
 		rv.SetFloat(value)
 		return nil
+	case reflect.Complex64, reflect.Complex128:
+		value, err := strconv.ParseComplex(tag.value, bitness(rt))
+		if err != nil {
+			return makeParseError(err)
+		}
+		rv.SetComplex(value)
+		return nil
 	case reflect.Bool:
-		value, err := strconv.ParseBool(tag.value)
+		value, err := tag.parseBool(tag.value)
 		if err != nil {
 			return makeParseError(err)
 		}
 		rv.SetBool(value)
 		return nil
-	case reflect.Slice:
-		entries := strings.Split(tag.value, tag.sep)
-		slice := reflect.MakeSlice(rt, 0, len(entries))
+	case reflect.Slice, reflect.Array:
+		// An explicitly empty value is an empty slice, rather than a slice with a
+		// single empty element.
+		var entries []string
+		if tag.value != "" {
+			var err error
+			if entries, err = tag.split(tag.value); err != nil {
+				return makeParseError(err)
+			}
+		}
+
+		var result reflect.Value
+		if rt.Kind() == reflect.Array {
+			if len(entries) != rt.Len() {
+				return makeParseError(fmt.Errorf("expected %d elements, got %d", rt.Len(), len(entries)))
+			}
+			result = reflect.New(rt).Elem()
+		} else {
+			result = reflect.MakeSlice(rt, len(entries), len(entries))
+		}
+		for i, entry := range entries {
+			if err := decodeValue(lookup, tag.elemTag(entry), name, rt.Elem(), result.Index(i), field); err != nil {
+				return makeParseError(&IndexError{
+					Key:   tag.key,
+					Index: i,
+					Value: tag.safeValue(entry),
+					Err:   err,
+				})
+			}
+		}
+		if tag.unique {
+			result = uniqueElems(result)
+		}
+		if err := tag.checkLength(rt, result.Len()); err != nil {
+			return err
+		}
+		rv.Set(result)
+		return nil
+	case reflect.Map:
+		result := reflect.MakeMap(rt)
+		if tag.value == "" {
+			if err := tag.checkLength(rt, 0); err != nil {
+				return err
+			}
+			rv.Set(result)
+			return nil
+		}
+		entries, err := tag.split(tag.value)
+		if err != nil {
+			return makeParseError(err)
+		}
 		for _, entry := range entries {
-			elem := reflect.New(rt.Elem()).Elem()
-			newTag := *tag
-			newTag.value = entry
-			if err := decodeValue(lookup, &newTag, name, rt.Elem(), elem, field); err != nil {
+			rawKey, rawValue, ok := strings.Cut(entry, tag.kvsep)
+			if !ok {
+				return makeParseError(fmt.Errorf("missing separator %q in entry %q", tag.kvsep, tag.safeValue(entry)))
+			}
+			key := reflect.New(rt.Key()).Elem()
+			if err := decodeValue(lookup, tag.elemTag(rawKey), name, rt.Key(), key, field); err != nil {
+				return makeParseError(err)
+			}
+			value := reflect.New(rt.Elem()).Elem()
+			if err := decodeValue(lookup, tag.elemTag(rawValue), name, rt.Elem(), value, field); err != nil {
 				return makeParseError(err)
 			}
-			slice = reflect.Append(slice, elem)
+			result.SetMapIndex(key, value)
 		}
-		rv.Set(slice)
+		if err := tag.checkLength(rt, result.Len()); err != nil {
+			return err
+		}
+		rv.Set(result)
 		return nil
 	default:
+		// Types such as sql.NullString are scanned from the raw string, which
+		// also marks them as valid.
+		if scanner, ok := rv.Addr().Interface().(sql.Scanner); ok {
+			if err := scanner.Scan(tag.value); err != nil {
+				return makeParseError(err)
+			}
+			return nil
+		}
+
+		// As a last resort, fall back to fmt.Scanner for types from other
+		// libraries that do not implement any of the unmarshaling interfaces.
+		if scanner, ok := rv.Addr().Interface().(fmt.Scanner); ok {
+			if _, err := fmt.Sscan(tag.value, scanner); err != nil {
+				return makeParseError(err)
+			}
+			return nil
+		}
 		return &InvalidTypeError{
 			Key:   tag.key,
 			Type:  rt,
@@ -360,7 +2012,17 @@ func decodeValue(lookup lookup, tag *tagOptions, name string, rt reflect.Type, r
 
 var (
 	durationType = reflect.TypeFor[time.Duration]()
+	fileModeType = reflect.TypeFor[os.FileMode]()
 	timeType     = reflect.TypeFor[time.Time]()
+	bigIntType   = reflect.TypeFor[big.Int]()
+	bigFloatType = reflect.TypeFor[big.Float]()
+	regexpType   = reflect.TypeFor[regexp.Regexp]()
+	valueType    = reflect.TypeFor[Value]()
+
+	unmarshalerType     = reflect.TypeFor[Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	scannerType         = reflect.TypeFor[fmt.Scanner]()
+	sqlScannerType      = reflect.TypeFor[sql.Scanner]()
 )
 
 // Get retrieves the value of the environment variable with the given key and
@@ -382,6 +2044,20 @@ func Get[T any](name string) (got T, err error) {
 	return
 }
 
+// MustGet retrieves the value of the environment variable with the given key
+// and unmarshals it into the provided type, panicking if this fails.
+//
+// This is intended for program initialization where a missing or malformed
+// environment variable should abort startup. The panic value is the error
+// returned from [Get], so recovering code may inspect it with [errors.As].
+func MustGet[T any](name string) T {
+	got, err := Get[T](name)
+	if err != nil {
+		panic(err)
+	}
+	return got
+}
+
 // GetOr retrieves the value of the environment variable with the given key and
 // unmarshals it into the provided type. If the environment variable is not set,
 // the fallback value is returned instead. This is a strongly-typed equivalent
@@ -397,3 +2073,22 @@ func GetOr[T any](name string, fallback T) (got T, err error) {
 	err = Value(value).Decode(&got)
 	return
 }
+
+// GetOrFunc retrieves the value of the environment variable with the given key
+// and unmarshals it into the provided type. If the environment variable is not
+// set, the fallback function is invoked and its result is returned instead.
+//
+// Unlike [GetOr], the fallback is computed lazily and is never invoked when the
+// environment variable is set. This is useful when computing the default is
+// expensive.
+//
+// This function will only return errors if the value cannot be unmarshaled into
+// the provided type correctly, or if the fallback function returns an error.
+func GetOrFunc[T any](name string, fallback func() (T, error)) (got T, err error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return fallback()
+	}
+	err = Value(value).Decode(&got)
+	return
+}
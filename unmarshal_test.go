@@ -1,10 +1,19 @@
 package env_test
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -529,3 +538,3967 @@ func TestGetOr(t *testing.T) {
 		})
 	}
 }
+
+func TestGetOrFunc(t *testing.T) {
+	errFallback := errors.New("fallback error")
+	testCases := []struct {
+		name       string
+		value      string
+		fallback   func() (int, error)
+		want       int
+		wantErr    error
+		wantCalled bool
+	}{
+		{
+			name:     "Value exists and parses correctly",
+			value:    "42",
+			fallback: func() (int, error) { return 0, nil },
+			want:     42,
+		}, {
+			name:       "Value does not exist",
+			fallback:   func() (int, error) { return 42, nil },
+			want:       42,
+			wantCalled: true,
+		}, {
+			name:       "Value does not exist and fallback fails",
+			fallback:   func() (int, error) { return 0, errFallback },
+			wantErr:    errFallback,
+			wantCalled: true,
+		}, {
+			name:     "Value exists but cannot be parsed",
+			value:    "Hello World",
+			fallback: func() (int, error) { return 0, nil },
+			wantErr:  env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.value != "" {
+				setenv(t, "VALUE=%s", tc.value)
+			}
+
+			called := false
+			got, err := env.GetOrFunc("VALUE", func() (int, error) {
+				called = true
+				return tc.fallback()
+			})
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("GetOrFunc(%s): got err '%v', want '%v'", tc.name, err, tc.wantErr)
+			}
+			if got, want := got, tc.want; got != want {
+				t.Errorf("GetOrFunc(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := called, tc.wantCalled; got != want {
+				t.Errorf("GetOrFunc(%s): fallback called = %v, want %v", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_FeatureGatedFields(t *testing.T) {
+	type FeatureEnv struct {
+		Stable       string `env:"STABLE"`
+		Experimental string `env:"EXPERIMENTAL_X,feature=beta,required"`
+		Alpha        int    `env:"EXPERIMENTAL_Y,feature=alpha"`
+	}
+
+	testCases := []struct {
+		name     string
+		features []string
+		want     FeatureEnv
+	}{
+		{
+			name: "No features enabled",
+			want: FeatureEnv{
+				Stable: "stable",
+			},
+		}, {
+			name:     "Beta feature enabled",
+			features: []string{"beta"},
+			want: FeatureEnv{
+				Stable:       "stable",
+				Experimental: "experimental",
+			},
+		}, {
+			name:     "All features enabled",
+			features: []string{"beta", "alpha"},
+			want: FeatureEnv{
+				Stable:       "stable",
+				Experimental: "experimental",
+				Alpha:        42,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setenv(t, `
+				STABLE=stable
+				EXPERIMENTAL_X=experimental
+				EXPERIMENTAL_Y=42
+			`)
+
+			var out FeatureEnv
+			err := env.Unmarshal(&out, env.WithFeatures(tc.features...))
+			if err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := out, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_FeatureDisabled_SkipsRequiredAndInvalidValues(t *testing.T) {
+	type FeatureEnv struct {
+		Required string `env:"FEATURE_REQUIRED,feature=beta,required"`
+		Invalid  int    `env:"FEATURE_INVALID,feature=beta"`
+	}
+	setenv(t, "FEATURE_INVALID=not_a_number")
+
+	var out FeatureEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := out, (FeatureEnv{}); !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_FeatureEnabled_EnforcesRequired(t *testing.T) {
+	type FeatureEnv struct {
+		Required string `env:"FEATURE_REQUIRED,feature=beta,required"`
+	}
+
+	var out FeatureEnv
+	err := env.Unmarshal(&out, env.WithFeatures("beta"))
+
+	if got, want := err, env.ErrRequirement; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestMustGet(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr error
+	}{
+		{
+			name:  "Value exists and parses correctly",
+			value: "42",
+			want:  42,
+		}, {
+			name:    "Value does not exist",
+			wantErr: env.ErrRequirement,
+		}, {
+			name:    "Value exists but cannot be parsed",
+			value:   "Hello World",
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.value != "" {
+				setenv(t, "VALUE=%s", tc.value)
+			}
+
+			var err error
+			got := func() int {
+				defer func() {
+					if r := recover(); r != nil {
+						var ok bool
+						if err, ok = r.(error); !ok {
+							t.Fatalf("MustGet(%s): panicked with non-error '%v'", tc.name, r)
+						}
+					}
+				}()
+				return env.MustGet[int]("VALUE")
+			}()
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("MustGet(%s): got panic '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := got, tc.want; got != want {
+				t.Errorf("MustGet(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestMustGet_PanicValue_IsTypedError(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+
+		var requiredErr *env.RequirementError
+		if !errors.As(err, &requiredErr) {
+			t.Fatalf("MustGet(): expected RequirementError, got %T", err)
+		}
+		if got, want := requiredErr.Key, "MUST_GET_MISSING"; got != want {
+			t.Errorf("MustGet(): got key '%v', want '%v'", got, want)
+		}
+	}()
+
+	env.MustGet[string]("MUST_GET_MISSING")
+}
+
+func TestUnmarshal_Complex(t *testing.T) {
+	type ComplexEnv struct {
+		Gain   complex128 `env:"GAIN"`
+		Offset complex64  `env:"OFFSET"`
+	}
+
+	setenv(t, `
+		GAIN=3+4i
+		OFFSET=-1.5i
+	`)
+
+	var out ComplexEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := ComplexEnv{
+		Gain:   complex(3, 4),
+		Offset: complex(0, -1.5),
+	}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_MalformedComplex_ReturnsParseError(t *testing.T) {
+	type ComplexEnv struct {
+		Gain complex128 `env:"GAIN"`
+	}
+	setenv(t, "GAIN=3+4j+")
+
+	var out ComplexEnv
+	err := env.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_BigNumbers(t *testing.T) {
+	type BigEnv struct {
+		MaxSupply *big.Int   `env:"MAX_SUPPLY"`
+		Ratio     *big.Float `env:"RATIO"`
+		Unset     *big.Int   `env:"UNSET_BIG_INT"`
+	}
+	setenv(t, `
+		MAX_SUPPLY=0x1000000000000000000
+		RATIO=0.5
+	`)
+
+	var out BigEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := out.MaxSupply, new(big.Int).Lsh(big.NewInt(1), 72); got == nil || got.Cmp(want) != 0 {
+		t.Errorf("Unmarshal(): got MaxSupply '%v', want '%v'", got, want)
+	}
+	if got, want := out.Ratio, big.NewFloat(0.5); got == nil || got.Cmp(want) != 0 {
+		t.Errorf("Unmarshal(): got Ratio '%v', want '%v'", got, want)
+	}
+	if got := out.Unset; got != nil {
+		t.Errorf("Unmarshal(): got Unset '%v', want nil", got)
+	}
+}
+
+func TestUnmarshal_RequiredIf(t *testing.T) {
+	type TLSEnv struct {
+		Cert    string `env:"TLS_CERT,requiredIf=TLS_ENABLED"`
+		Enabled bool   `env:"TLS_ENABLED"`
+	}
+
+	testCases := []struct {
+		name        string
+		environment string
+		want        TLSEnv
+		wantErr     error
+	}{
+		{
+			name:        "Condition unset",
+			environment: "",
+			want:        TLSEnv{},
+		}, {
+			name:        "Condition false",
+			environment: "TLS_ENABLED=false",
+			want:        TLSEnv{},
+		}, {
+			name:        "Condition true and field set",
+			environment: "TLS_ENABLED=true\nTLS_CERT=cert.pem",
+			want:        TLSEnv{Cert: "cert.pem", Enabled: true},
+		}, {
+			name:        "Condition true and field unset",
+			environment: "TLS_ENABLED=true",
+			wantErr:     env.ErrRequirement,
+		}, {
+			name:        "Condition non-boolean and field unset",
+			environment: "TLS_ENABLED=yes",
+			wantErr:     env.ErrRequirement,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setenv(t, tc.environment)
+
+			var out TLSEnv
+			err := env.Unmarshal(&out)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := out, tc.want; tc.wantErr == nil && !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_RequiredIfConditionMet_ErrorNamesBothKeys(t *testing.T) {
+	type TLSEnv struct {
+		Cert string `env:"TLS_CERT,requiredIf=TLS_ENABLED"`
+	}
+	setenv(t, "TLS_ENABLED=1")
+
+	var out TLSEnv
+	err := env.Unmarshal(&out)
+
+	var requiredErr *env.RequirementError
+	if !errors.As(err, &requiredErr) {
+		t.Fatalf("Unmarshal(): expected RequirementError, got %T", err)
+	}
+	if got, want := requiredErr.Key, "TLS_CERT"; got != want {
+		t.Errorf("Unmarshal(): got key '%v', want '%v'", got, want)
+	}
+	if got, want := requiredErr.Condition, "TLS_ENABLED"; got != want {
+		t.Errorf("Unmarshal(): got condition '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_ExclusiveGroup(t *testing.T) {
+	type AuthEnv struct {
+		APIKey     string `env:"API_KEY,group=auth,required"`
+		OAuthToken string `env:"OAUTH_TOKEN,group=auth"`
+		Password   string `env:"PASSWORD,group=basic"`
+		Token      string `env:"TOKEN,group=basic"`
+	}
+
+	testCases := []struct {
+		name        string
+		environment string
+		want        AuthEnv
+		wantKeys    []string
+		wantErr     error
+	}{
+		{
+			name:        "Single member set",
+			environment: "OAUTH_TOKEN=token",
+			want:        AuthEnv{OAuthToken: "token"},
+		}, {
+			name:        "Multiple members set",
+			environment: "API_KEY=key\nOAUTH_TOKEN=token",
+			wantKeys:    []string{"API_KEY", "OAUTH_TOKEN"},
+			wantErr:     env.ErrExclusiveGroup,
+		}, {
+			name:        "Required group with no members set",
+			environment: "PASSWORD=password",
+			wantErr:     env.ErrExclusiveGroup,
+		}, {
+			name:        "Optional group with no members set",
+			environment: "API_KEY=key",
+			want:        AuthEnv{APIKey: "key"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setenv(t, tc.environment)
+
+			var out AuthEnv
+			err := env.Unmarshal(&out)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if tc.wantErr != nil {
+				var groupErr *env.ExclusiveGroupError
+				if !errors.As(err, &groupErr) {
+					t.Fatalf("Unmarshal(%s): expected ExclusiveGroupError, got %T", tc.name, err)
+				}
+				if got, want := groupErr.Group, "auth"; got != want {
+					t.Errorf("Unmarshal(%s): got group '%v', want '%v'", tc.name, got, want)
+				}
+				if got, want := groupErr.Keys, tc.wantKeys; !cmp.Equal(got, want, cmpopts.EquateEmpty()) {
+					t.Errorf("Unmarshal(%s): got keys '%v', want '%v'", tc.name, got, want)
+				}
+				return
+			}
+			if got, want := out, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_TrimOptions(t *testing.T) {
+	type TrimEnv struct {
+		Port    int      `env:"PORT,trim"`
+		Name    string   `env:"NAME"`
+		Version string   `env:"VERSION,trimprefix=v"`
+		Size    int      `env:"SIZE,trim,trimsuffix=px"`
+		Hosts   []string `env:"HOSTS,trim"`
+		Tags    []string `env:"TAGS,trimprefix=#"`
+	}
+
+	setenv(t, `
+		VERSION=v1.2.3
+		HOSTS= a , b ,c
+		TAGS=#go,#env
+	`)
+	// setenv trims each line, so surrounding whitespace is set explicitly.
+	t.Setenv("SIZE", " 42px")
+	t.Setenv("PORT", " 8080 ")
+	t.Setenv("NAME", " padded ")
+
+	var out TrimEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := TrimEnv{
+		Port:    8080,
+		Name:    " padded ",
+		Version: "1.2.3",
+		Size:    42,
+		Hosts:   []string{"a", "b", "c"},
+		Tags:    []string{"go", "env"},
+	}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_UntrimmedNumeric_ReturnsParseError(t *testing.T) {
+	type PortEnv struct {
+		Port int `env:"PORT"`
+	}
+	t.Setenv("PORT", " 8080 ")
+
+	var out PortEnv
+	err := env.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_CaseOptions(t *testing.T) {
+	type Level string
+	type CaseEnv struct {
+		Lower   string   `env:"LOG_LEVEL,lower"`
+		Upper   *string  `env:"REGION,upper"`
+		Named   Level    `env:"NAMED_LEVEL,lower"`
+		Slice   []string `env:"LEVELS,upper"`
+		Default string   `env:"MIXED"`
+	}
+	setenv(t, `
+		LOG_LEVEL=Info
+		REGION=us-east-1
+		NAMED_LEVEL=WARN
+		LEVELS=debug,Info,WARN
+		MIXED=MiXeD
+	`)
+
+	var out CaseEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := CaseEnv{
+		Lower:   "info",
+		Upper:   ptr("US-EAST-1"),
+		Named:   "warn",
+		Slice:   []string{"DEBUG", "INFO", "WARN"},
+		Default: "MiXeD",
+	}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_CaseOptionOnNonString_ReturnsError(t *testing.T) {
+	testCases := []struct {
+		name string
+		out  any
+	}{
+		{
+			name: "lower on int",
+			out: &struct {
+				Value int `env:"VALUE,lower"`
+			}{},
+		}, {
+			name: "upper on duration slice",
+			out: &struct {
+				Value []time.Duration `env:"VALUE,upper"`
+			}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := env.Unmarshal(tc.out)
+
+			if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_OneOf(t *testing.T) {
+	type LevelEnv struct {
+		Level  string   `env:"LOG_LEVEL,lower,oneof=debug info warn error"`
+		Levels []string `env:"LOG_LEVELS,oneof=debug info"`
+	}
+
+	testCases := []struct {
+		name        string
+		environment string
+		want        LevelEnv
+		wantErr     error
+	}{
+		{
+			name:        "Allowed value",
+			environment: "LOG_LEVEL=warn",
+			want:        LevelEnv{Level: "warn"},
+		}, {
+			name:        "Allowed value after case normalization",
+			environment: "LOG_LEVEL=INFO",
+			want:        LevelEnv{Level: "info"},
+		}, {
+			name:        "Disallowed value",
+			environment: "LOG_LEVEL=verbose",
+			wantErr:     env.ErrValidation,
+		}, {
+			name:        "Allowed slice values",
+			environment: "LOG_LEVELS=debug,info",
+			want:        LevelEnv{Levels: []string{"debug", "info"}},
+		}, {
+			name:        "Disallowed slice value",
+			environment: "LOG_LEVELS=debug,warn",
+			wantErr:     env.ErrValidation,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setenv(t, tc.environment)
+
+			var out LevelEnv
+			err := env.Unmarshal(&out)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := out, tc.want; tc.wantErr == nil && !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_OneOfOnNonString_ReturnsError(t *testing.T) {
+	type InvalidEnv struct {
+		Value int `env:"VALUE,oneof=1 2 3"`
+	}
+
+	var out InvalidEnv
+	err := env.Unmarshal(&out)
+
+	if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_RangeOptions(t *testing.T) {
+	type RangeEnv struct {
+		Port   int       `env:"PORT,min=1,max=65535"`
+		Ratio  float64   `env:"RATIO,min=0,max=0.5"`
+		Count  uint      `env:"COUNT,max=10"`
+		Delays []int     `env:"DELAYS,min=0"`
+		Scale  *float32  `env:"SCALE,min=-1.5"`
+		Bounds []float64 `env:"BOUNDS,max=1"`
+	}
+
+	testCases := []struct {
+		name        string
+		environment string
+		want        RangeEnv
+		wantErr     error
+	}{
+		{
+			name:        "Values within bounds",
+			environment: "PORT=8080\nRATIO=0.5\nCOUNT=10\nSCALE=-1.5",
+			want:        RangeEnv{Port: 8080, Ratio: 0.5, Count: 10, Scale: ptr(float32(-1.5))},
+		}, {
+			name:        "Integer below min",
+			environment: "PORT=0",
+			wantErr:     env.ErrValidation,
+		}, {
+			name:        "Integer above max",
+			environment: "PORT=65536",
+			wantErr:     env.ErrValidation,
+		}, {
+			name:        "Float above max",
+			environment: "RATIO=0.51",
+			wantErr:     env.ErrValidation,
+		}, {
+			name:        "Unsigned above max",
+			environment: "COUNT=11",
+			wantErr:     env.ErrValidation,
+		}, {
+			name:        "Slice element below min",
+			environment: "DELAYS=1,-1",
+			wantErr:     env.ErrValidation,
+		}, {
+			name:        "Pointer below min",
+			environment: "SCALE=-2",
+			wantErr:     env.ErrValidation,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setenv(t, tc.environment)
+
+			var out RangeEnv
+			err := env.Unmarshal(&out)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := out, tc.want; tc.wantErr == nil && !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_RangeViolation_NamesKeyAndBound(t *testing.T) {
+	type PortEnv struct {
+		Port int `env:"PORT,min=1,max=65535"`
+	}
+	setenv(t, "PORT=70000")
+
+	var out PortEnv
+	err := env.Unmarshal(&out)
+
+	var validationErr *env.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Unmarshal(): expected ValidationError, got %T", err)
+	}
+	if got, want := validationErr.Key, "PORT"; got != want {
+		t.Errorf("Unmarshal(): got key '%v', want '%v'", got, want)
+	}
+	if got, want := err.Error(), "65535"; !strings.Contains(got, want) {
+		t.Errorf("Unmarshal(): got error '%v', want it to contain '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_InvalidRangeOptions_ReturnsError(t *testing.T) {
+	testCases := []struct {
+		name string
+		out  any
+	}{
+		{
+			name: "min on string",
+			out: &struct {
+				Value string `env:"VALUE,min=1"`
+			}{},
+		}, {
+			name: "max on duration",
+			out: &struct {
+				Value time.Duration `env:"VALUE,max=1"`
+			}{},
+		}, {
+			name: "non-numeric bound",
+			out: &struct {
+				Value int `env:"VALUE,min=one"`
+			}{},
+		}, {
+			name: "min greater than max",
+			out: &struct {
+				Value int `env:"VALUE,min=10,max=1"`
+			}{},
+		}, {
+			name: "presence on string",
+			out: &struct {
+				Value string `env:"VALUE,presence"`
+			}{},
+		}, {
+			name: "presence with required",
+			out: &struct {
+				Value bool `env:"VALUE,required,presence"`
+			}{},
+		}, {
+			name: "minlen on string",
+			out: &struct {
+				Value string `env:"VALUE,minlen=1"`
+			}{},
+		}, {
+			name: "negative maxlen",
+			out: &struct {
+				Value []string `env:"VALUE,maxlen=-1"`
+			}{},
+		}, {
+			name: "minlen greater than maxlen",
+			out: &struct {
+				Value []string `env:"VALUE,minlen=2,maxlen=1"`
+			}{},
+		}, {
+			name: "base on string",
+			out: &struct {
+				Value string `env:"VALUE,base=16"`
+			}{},
+		}, {
+			name: "base on float",
+			out: &struct {
+				Value float64 `env:"VALUE,base=16"`
+			}{},
+		}, {
+			name: "base on duration",
+			out: &struct {
+				Value time.Duration `env:"VALUE,base=16"`
+			}{},
+		}, {
+			name: "base out of range",
+			out: &struct {
+				Value int `env:"VALUE,base=37"`
+			}{},
+		}, {
+			name: "non-numeric base",
+			out: &struct {
+				Value int `env:"VALUE,base=hex"`
+			}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := env.Unmarshal(tc.out)
+
+			if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_StructSlice(t *testing.T) {
+	type Upstream struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+	}
+	type UpstreamEnv struct {
+		Upstreams []Upstream `env:"UPSTREAM"`
+	}
+	setenv(t, `
+		UPSTREAM_0_HOST=a.example.com
+		UPSTREAM_1_PORT=8080
+	`)
+
+	var out UpstreamEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := UpstreamEnv{
+		Upstreams: []Upstream{
+			{Host: "a.example.com"},
+			{Port: 8080},
+		},
+	}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_ExtendedBool(t *testing.T) {
+	type BoolEnv struct {
+		Yes      bool   `env:"YES"`
+		Off      bool   `env:"OFF"`
+		Enabled  *bool  `env:"ENABLED"`
+		Disabled bool   `env:"DISABLED"`
+		Strict   bool   `env:"STRICT"`
+		Slice    []bool `env:"SLICE"`
+	}
+	setenv(t, `
+		YES=Yes
+		OFF=off
+		ENABLED=ENABLED
+		DISABLED=disabled
+		STRICT=true
+		SLICE=on,no,1
+	`)
+
+	var out BoolEnv
+	err := env.Unmarshal(&out, env.ExtendedBool())
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := BoolEnv{
+		Yes:      true,
+		Off:      false,
+		Enabled:  ptr(true),
+		Disabled: false,
+		Strict:   true,
+		Slice:    []bool{true, false, true},
+	}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_ExtendedBoolToken_ReturnsParseError(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		opts  []env.UnmarshalOption
+	}{
+		{
+			name:  "Strict by default",
+			value: "yes",
+		}, {
+			name:  "Unknown extended token",
+			value: "maybe",
+			opts:  []env.UnmarshalOption{env.ExtendedBool()},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			type BoolEnv struct {
+				Bool bool `env:"BOOL"`
+			}
+			t.Setenv("BOOL", tc.value)
+
+			var out BoolEnv
+			err := env.Unmarshal(&out, tc.opts...)
+
+			if got, want := err, env.ErrParse; !errors.Is(got, want) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_BoolParser(t *testing.T) {
+	type BoolEnv struct {
+		Enabled bool   `env:"ENABLED"`
+		Slice   []bool `env:"SLICE"`
+	}
+	setenv(t, `
+		ENABLED=sí
+		SLICE=sí,no
+	`)
+	parse := func(s string) (bool, error) {
+		return s == "sí", nil
+	}
+
+	var out BoolEnv
+	err := env.Unmarshal(&out, env.BoolParser(parse))
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := BoolEnv{
+		Enabled: true,
+		Slice:   []bool{true, false},
+	}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshalContext(t *testing.T) {
+	type ContextEnv struct {
+		Name string `env:"NAME"`
+	}
+	setenv(t, "NAME=example")
+
+	var out ContextEnv
+	err := env.UnmarshalContext(context.Background(), &out)
+	if err != nil {
+		t.Fatalf("UnmarshalContext(): unexpected error: %v", err)
+	}
+
+	want := ContextEnv{Name: "example"}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("UnmarshalContext(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshalContext_Cancelled_ReturnsError(t *testing.T) {
+	type ContextEnv struct {
+		Name string `env:"NAME"`
+	}
+	setenv(t, "NAME=example")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out ContextEnv
+	err := env.UnmarshalContext(ctx, &out)
+
+	if got, want := err, context.Canceled; !errors.Is(got, want) {
+		t.Errorf("UnmarshalContext(): got err '%v', want '%v'", got, want)
+	}
+	if got, want := out, (ContextEnv{}); !cmp.Equal(got, want) {
+		t.Errorf("UnmarshalContext(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_WithLookup(t *testing.T) {
+	type LookupEnv struct {
+		Name string `env:"NAME"`
+		Port int    `env:"PORT"`
+	}
+	setenv(t, `
+		NAME=from-os
+		PORT=80
+	`)
+	source := map[string]string{
+		"NAME": "from-lookup",
+	}
+	lookup := func(key string) (string, bool) {
+		value, ok := source[key]
+		return value, ok
+	}
+
+	var out LookupEnv
+	err := env.Unmarshal(&out, env.WithLookup(lookup))
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := LookupEnv{Name: "from-lookup"}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_WithLookupContext(t *testing.T) {
+	type LookupEnv struct {
+		Name string `env:"NAME"`
+		Port int    `env:"PORT"`
+	}
+	type contextKey struct{}
+	ctx := context.WithValue(context.Background(), contextKey{}, "from-context")
+	lookup := func(ctx context.Context, key string) (string, bool, error) {
+		if key != "NAME" {
+			return "", false, nil
+		}
+		value, ok := ctx.Value(contextKey{}).(string)
+		return value, ok, nil
+	}
+
+	var out LookupEnv
+	err := env.UnmarshalContext(ctx, &out, env.WithLookupContext(lookup))
+	if err != nil {
+		t.Fatalf("UnmarshalContext(): unexpected error: %v", err)
+	}
+
+	want := LookupEnv{Name: "from-context"}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("UnmarshalContext(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_WithLookupContextError_ReturnsError(t *testing.T) {
+	type LookupEnv struct {
+		Name string `env:"NAME,required"`
+		Port int    `env:"PORT"`
+	}
+	errLookup := errors.New("secret store unavailable")
+	var calls int
+	lookup := func(ctx context.Context, key string) (string, bool, error) {
+		calls++
+		return "", false, errLookup
+	}
+
+	var out LookupEnv
+	err := env.Unmarshal(&out, env.WithLookupContext(lookup))
+
+	if got, want := err, errLookup; !errors.Is(got, want) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+	if got, want := calls, 1; got != want {
+		t.Errorf("Unmarshal(): got %v lookups, want %v", got, want)
+	}
+}
+
+func TestUnmarshalContext_CancelledDuringLookup_ReturnsError(t *testing.T) {
+	type LookupEnv struct {
+		Name string `env:"NAME"`
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lookup := func(ctx context.Context, key string) (string, bool, error) {
+		cancel()
+		<-ctx.Done()
+		return "", false, ctx.Err()
+	}
+
+	var out LookupEnv
+	err := env.UnmarshalContext(ctx, &out, env.WithLookupContext(lookup))
+
+	if got, want := err, context.Canceled; !errors.Is(got, want) {
+		t.Errorf("UnmarshalContext(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	environment := env.Environment{
+		"PTR_STRING":     "Hello World",
+		"STRING":         "Hello",
+		"BOOL":           "true",
+		"INT":            "42",
+		"UINT":           "42",
+		"FLOAT64":        "4.2",
+		"DURATION":       "5s",
+		"TIME":           "2021-01-01T00:00:00Z",
+		"STRING_SLICE":   "Hello;World",
+		"DURATION_SLICE": "1s,1m",
+	}
+	for i := 0; i < b.N; i++ {
+		var out OptionalEnv
+		if err := environment.Unmarshal(&out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEnvironmentUnmarshal_Concurrent(t *testing.T) {
+	type ConcurrentEnv struct {
+		Name string   `env:"NAME,required"`
+		Port int      `env:"PORT,min=1"`
+		Tags []string `env:"TAGS,sep=;"`
+	}
+	environment := env.Environment{
+		"NAME": "example",
+		"PORT": "8080",
+		"TAGS": "a;b",
+	}
+	want := ConcurrentEnv{
+		Name: "example",
+		Port: 8080,
+		Tags: []string{"a", "b"},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var out ConcurrentEnv
+			if err := environment.Unmarshal(&out); err != nil {
+				t.Errorf("Environment.Unmarshal(): unexpected error: %v", err)
+				return
+			}
+			if got := out; !cmp.Equal(got, want) {
+				t.Errorf("Environment.Unmarshal(): got '%v', want '%v'", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestUnmarshal_SecretParseError_DoesNotLeakValue(t *testing.T) {
+	type SecretEnv struct {
+		Token  int   `env:"TOKEN,secret"`
+		Tokens []int `env:"TOKENS,secret"`
+	}
+	testCases := []struct {
+		name   string
+		key    string
+		secret string
+	}{
+		{
+			name:   "Scalar",
+			key:    "TOKEN",
+			secret: "hunter2",
+		}, {
+			name:   "Slice",
+			key:    "TOKENS",
+			secret: "1,hunter2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(tc.key, tc.secret)
+
+			var out SecretEnv
+			err := env.Unmarshal(&out)
+
+			var parseErr *env.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want ParseError", tc.name, err)
+			}
+			if got, want := parseErr.Value, "***"; got != want {
+				t.Errorf("Unmarshal(%s): got value '%v', want '%v'", tc.name, got, want)
+			}
+			if got := err.Error(); strings.Contains(got, "hunter2") {
+				t.Errorf("Unmarshal(%s): error '%v' leaks secret value", tc.name, got)
+			}
+			if !errors.Is(err, strconv.ErrSyntax) {
+				t.Errorf("Unmarshal(%s): got err '%v', want it to wrap '%v'", tc.name, err, strconv.ErrSyntax)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_ShortSecretParseError_DoesNotRevealValue(t *testing.T) {
+	type SecretEnv struct {
+		N int `env:"N,secret"`
+	}
+	setenv(t, "N=r")
+
+	var out SecretEnv
+	err := env.Unmarshal(&out)
+
+	if err == nil {
+		t.Fatalf("Unmarshal(): got nil err, want ParseError")
+	}
+	want := "env: unable to parse N from env variable int: invalid secret value"
+	if got := err.Error(); got != want {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Unmarshal(): got err '%v', want it to wrap '%v'", err, strconv.ErrSyntax)
+	}
+}
+
+func TestUnmarshal_SecretValidationError_DoesNotLeakValue(t *testing.T) {
+	type SecretEnv struct {
+		Mode string `env:"MODE,secret,oneof=a b"`
+	}
+	setenv(t, "MODE=hunter2")
+
+	var out SecretEnv
+	err := env.Unmarshal(&out)
+
+	var validationErr *env.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Unmarshal(): got err '%v', want ValidationError", err)
+	}
+	if got, want := validationErr.Value, "***"; got != want {
+		t.Errorf("Unmarshal(): got value '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_ParseError_MasksValue(t *testing.T) {
+	type ParseEnv struct {
+		Port int `env:"PORT"`
+	}
+	testCases := []struct {
+		name      string
+		opts      []env.UnmarshalOption
+		wantValue bool
+	}{
+		{
+			name:      "Masked by default",
+			wantValue: false,
+		}, {
+			name:      "Verbose errors",
+			opts:      []env.UnmarshalOption{env.VerboseErrors()},
+			wantValue: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setenv(t, "PORT=not_a_port")
+
+			var out ParseEnv
+			err := env.Unmarshal(&out, tc.opts...)
+
+			var parseErr *env.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want ParseError", tc.name, err)
+			}
+			if got, want := parseErr.Value, "not_a_port"; got != want {
+				t.Errorf("Unmarshal(%s): got value '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := strings.Contains(err.Error(), "not_a_port"), tc.wantValue; got != want {
+				t.Errorf("Unmarshal(%s): got error '%v', want value included '%v'", tc.name, err, want)
+			}
+			if !errors.Is(err, strconv.ErrSyntax) {
+				t.Errorf("Unmarshal(%s): got err '%v', want it to wrap '%v'", tc.name, err, strconv.ErrSyntax)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_SliceElementError_ReportsIndex(t *testing.T) {
+	type SliceEnv struct {
+		Durations []time.Duration `env:"DURATION_SLICE"`
+	}
+	setenv(t, "DURATION_SLICE=5s,bogus,5h")
+
+	var out SliceEnv
+	err := env.Unmarshal(&out)
+
+	var indexErr *env.IndexError
+	if !errors.As(err, &indexErr) {
+		t.Fatalf("Unmarshal(): got err '%v', want IndexError", err)
+	}
+	if got, want := indexErr.Index, 1; got != want {
+		t.Errorf("Unmarshal(): got index '%v', want '%v'", got, want)
+	}
+	if got, want := indexErr.Value, "bogus"; got != want {
+		t.Errorf("Unmarshal(): got value '%v', want '%v'", got, want)
+	}
+	if got, want := err.Error(), "element 1 of DURATION_SLICE"; !strings.Contains(got, want) {
+		t.Errorf("Unmarshal(): got error '%v', want it to contain '%v'", got, want)
+	}
+	if got, want := err, env.ErrParse; !errors.Is(got, want) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_Maps(t *testing.T) {
+	type MapEnv struct {
+		Labels   map[string]string        `env:"LABELS,sep=;"`
+		Limits   map[string]int           `env:"LIMITS,kvsep=:"`
+		Timeouts map[string]time.Duration `env:"TIMEOUTS,trim"`
+		Empty    map[string]string        `env:"EMPTY"`
+		Unset    map[string]string        `env:"UNSET"`
+	}
+	setenv(t, `
+		LABELS=env=prod;team=payments
+		LIMITS=cpu:2,memory:512
+		TIMEOUTS=read = 5s, write = 10s
+		EMPTY=
+	`)
+
+	var out MapEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := MapEnv{
+		Labels:   map[string]string{"env": "prod", "team": "payments"},
+		Limits:   map[string]int{"cpu": 2, "memory": 512},
+		Timeouts: map[string]time.Duration{"read": 5 * time.Second, "write": 10 * time.Second},
+		Empty:    map[string]string{},
+	}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_MalformedMap_ReturnsParseError(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+	}{
+		{
+			name:  "Missing key-value separator",
+			value: "env=prod,team",
+		}, {
+			name:  "Invalid value type",
+			value: "cpu=two",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			type MapEnv struct {
+				Limits map[string]int `env:"LIMITS"`
+			}
+			t.Setenv("LIMITS", tc.value)
+
+			var out MapEnv
+			err := env.Unmarshal(&out)
+
+			if got, want := err, env.ErrParse; !errors.Is(got, want) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_SliceValues(t *testing.T) {
+	type SliceEnv struct {
+		Slice []string `env:"SLICE,sep=;"`
+	}
+	testCases := []struct {
+		name  string
+		value *string
+		want  []string
+	}{
+		{
+			name:  "Unset",
+			value: nil,
+			want:  nil,
+		}, {
+			name:  "Empty",
+			value: ptr(""),
+			want:  []string{},
+		}, {
+			name:  "Single element",
+			value: ptr("a"),
+			want:  []string{"a"},
+		}, {
+			name:  "Multiple elements",
+			value: ptr("a;b;c"),
+			want:  []string{"a", "b", "c"},
+		}, {
+			name:  "Empty elements",
+			value: ptr(";"),
+			want:  []string{"", ""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			environment := env.SealedEnvironment{}
+			if tc.value != nil {
+				environment.Set("SLICE", env.Value(*tc.value))
+			}
+
+			var out SliceEnv
+			err := environment.Unmarshal(&out)
+			if err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := out.Slice, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%#v', want '%#v'", tc.name, got, want)
+			}
+			if got, want := out.Slice == nil, tc.want == nil; got != want {
+				t.Errorf("Unmarshal(%s): got nil '%v', want nil '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_PointerSlices(t *testing.T) {
+	type PointerSliceEnv struct {
+		Ints       []*int           `env:"INTS"`
+		Strings    *[]string        `env:"STRINGS"`
+		Durations  []*time.Duration `env:"DURATIONS"`
+		DoublePtrs **[]*int         `env:"DOUBLE_PTRS"`
+		Unset      *[]string        `env:"UNSET"`
+	}
+
+	sut := env.SealedEnvironment{
+		"INTS":        "1,2,3",
+		"STRINGS":     "a,b",
+		"DURATIONS":   "1s,2m",
+		"DOUBLE_PTRS": "4,5",
+	}
+	doublePtr := &[]*int{ptr(4), ptr(5)}
+	want := PointerSliceEnv{
+		Ints:       []*int{ptr(1), ptr(2), ptr(3)},
+		Strings:    &[]string{"a", "b"},
+		Durations:  []*time.Duration{ptr(time.Second), ptr(2 * time.Minute)},
+		DoublePtrs: &doublePtr,
+	}
+
+	var got PointerSliceEnv
+	if err := sut.Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+	if got.Ints[0] == got.Ints[1] {
+		t.Errorf("Unmarshal(): got shared element pointers, want distinct allocations")
+	}
+}
+
+func TestUnmarshal_PointerSliceElementInvalid_LeavesPointerNil(t *testing.T) {
+	var out struct {
+		Ints *[]int `env:"INTS"`
+	}
+
+	err := env.SealedEnvironment{"INTS": "1,abc"}.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+	if out.Ints != nil {
+		t.Errorf("Unmarshal(): got '%v', want nil", out.Ints)
+	}
+}
+
+func TestUnmarshal_Arrays(t *testing.T) {
+	type ArrayEnv struct {
+		Color  [3]uint8         `env:"COLOR"`
+		Levels [2]string        `env:"LEVELS,sep=;,upper"`
+		Ptr    *[2]int          `env:"PTR"`
+		Times  [1]time.Duration `env:"TIMES"`
+	}
+	setenv(t, `
+		COLOR=255,128,0
+		LEVELS=debug;info
+		PTR=1,2
+		TIMES=5s
+	`)
+
+	var out ArrayEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := ArrayEnv{
+		Color:  [3]uint8{255, 128, 0},
+		Levels: [2]string{"DEBUG", "INFO"},
+		Ptr:    &[2]int{1, 2},
+		Times:  [1]time.Duration{5 * time.Second},
+	}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_ArrayLengthMismatch_ReturnsParseError(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+	}{
+		{
+			name:  "Too short",
+			value: "255,128",
+		}, {
+			name:  "Too long",
+			value: "255,128,0,1",
+		}, {
+			name:  "Empty",
+			value: "",
+		}, {
+			name:  "Invalid element",
+			value: "255,128,256",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			type ArrayEnv struct {
+				Color [3]uint8 `env:"COLOR"`
+			}
+			t.Setenv("COLOR", tc.value)
+
+			var out ArrayEnv
+			err := env.Unmarshal(&out)
+
+			if got, want := err, env.ErrParse; !errors.Is(got, want) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := out, (ArrayEnv{}); !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+type Point struct {
+	X, Y int
+}
+
+func (p *Point) Scan(state fmt.ScanState, verb rune) error {
+	_, err := fmt.Fscanf(state, "%d:%d", &p.X, &p.Y)
+	return err
+}
+
+func TestUnmarshal_Scanner(t *testing.T) {
+	type ScannerEnv struct {
+		Point    Point   `env:"POINT"`
+		PtrPoint *Point  `env:"PTR_POINT"`
+		Points   []Point `env:"POINTS"`
+		Unset    Point   `env:"UNSET"`
+	}
+	setenv(t, `
+		POINT=1:2
+		PTR_POINT=3:4
+		POINTS=5:6,7:8
+	`)
+
+	var out ScannerEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := ScannerEnv{
+		Point:    Point{X: 1, Y: 2},
+		PtrPoint: &Point{X: 3, Y: 4},
+		Points:   []Point{{X: 5, Y: 6}, {X: 7, Y: 8}},
+	}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_ScannerFails_ReturnsParseError(t *testing.T) {
+	type ScannerEnv struct {
+		Point Point `env:"POINT"`
+	}
+	setenv(t, "POINT=not_a_point")
+
+	var out ScannerEnv
+	err := env.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !errors.Is(got, want) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_Regexp(t *testing.T) {
+	type RegexpEnv struct {
+		Pattern  *regexp.Regexp   `env:"PATH_REGEX"`
+		Patterns []*regexp.Regexp `env:"PATTERNS,sep=;"`
+		Unset    *regexp.Regexp   `env:"UNSET"`
+	}
+	setenv(t, `
+		PATH_REGEX=^/api/
+		PATTERNS=a+;b,c
+	`)
+
+	var out RegexpEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := out.Pattern.String(), "^/api/"; got != want {
+		t.Errorf("Unmarshal(): got pattern '%v', want '%v'", got, want)
+	}
+	if !out.Pattern.MatchString("/api/users") {
+		t.Errorf("Unmarshal(): got pattern that does not match '/api/users'")
+	}
+	if got, want := len(out.Patterns), 2; got != want {
+		t.Fatalf("Unmarshal(): got %d patterns, want %d", got, want)
+	}
+	if got, want := out.Patterns[1].String(), "b,c"; got != want {
+		t.Errorf("Unmarshal(): got pattern '%v', want '%v'", got, want)
+	}
+	if out.Unset != nil {
+		t.Errorf("Unmarshal(): got unset pattern '%v', want nil", out.Unset)
+	}
+}
+
+func TestUnmarshal_InvalidRegexp_ReturnsParseError(t *testing.T) {
+	type RegexpEnv struct {
+		Pattern *regexp.Regexp `env:"PATH_REGEX"`
+	}
+	setenv(t, "PATH_REGEX=[unterminated")
+
+	var out RegexpEnv
+	err := env.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !errors.Is(got, want) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_SlogLevel(t *testing.T) {
+	type LevelEnv struct {
+		Level    slog.Level   `env:"LOG_LEVEL"`
+		PtrLevel *slog.Level  `env:"PTR_LOG_LEVEL"`
+		Levels   []slog.Level `env:"LOG_LEVELS"`
+		Offset   slog.Level   `env:"OFFSET_LOG_LEVEL"`
+	}
+	setenv(t, `
+		LOG_LEVEL=warn
+		PTR_LOG_LEVEL=DEBUG
+		LOG_LEVELS=info,error
+		OFFSET_LOG_LEVEL=INFO+2
+	`)
+
+	var out LevelEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := LevelEnv{
+		Level:    slog.LevelWarn,
+		PtrLevel: ptr(slog.LevelDebug),
+		Levels:   []slog.Level{slog.LevelInfo, slog.LevelError},
+		Offset:   slog.LevelInfo + 2,
+	}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_TextUnmarshalerIntKind_TakesPrecedenceOverKind(t *testing.T) {
+	type LevelEnv struct {
+		Level slog.Level `env:"LOG_LEVEL"`
+	}
+	testCases := []struct {
+		name    string
+		value   string
+		want    slog.Level
+		wantErr error
+	}{
+		{
+			name:  "Text form",
+			value: "error",
+			want:  slog.LevelError,
+		}, {
+			name:    "Integer form is not accepted",
+			value:   "8",
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("LOG_LEVEL", tc.value)
+
+			var out LevelEnv
+			err := env.Unmarshal(&out)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := out.Level, tc.want; got != want {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+// Ordinal is an int-kinded Unmarshaler that only accepts spelled-out numbers,
+// and records which unmarshaling interfaces were used.
+type Ordinal int
+
+var ordinalCalls []string
+
+func (o *Ordinal) UnmarshalEnv(b []byte) error {
+	ordinalCalls = append(ordinalCalls, "UnmarshalEnv")
+	switch string(b) {
+	case "one":
+		*o = 1
+	case "two":
+		*o = 2
+	default:
+		return fmt.Errorf("unknown ordinal %q", b)
+	}
+	return nil
+}
+
+func (o *Ordinal) UnmarshalText(text []byte) error {
+	ordinalCalls = append(ordinalCalls, "UnmarshalText")
+	*o = -1
+	return nil
+}
+
+func TestUnmarshal_UnmarshalerIntKind_DecodesOnce(t *testing.T) {
+	type OrdinalEnv struct {
+		Ordinal  Ordinal   `env:"ORDINAL"`
+		Ordinals []Ordinal `env:"ORDINALS"`
+	}
+	setenv(t, `
+		ORDINAL=two
+		ORDINALS=one,two
+	`)
+	ordinalCalls = nil
+
+	var out OrdinalEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := OrdinalEnv{
+		Ordinal:  2,
+		Ordinals: []Ordinal{1, 2},
+	}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+	wantCalls := []string{"UnmarshalEnv", "UnmarshalEnv", "UnmarshalEnv"}
+	if got, want := ordinalCalls, wantCalls; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got calls '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_PointerUnmarshalers(t *testing.T) {
+	testCases := []struct {
+		name  string
+		out   any
+		value string
+		get   func(out any) any
+		want  any
+	}{
+		{
+			name: "*Custom",
+			out: &struct {
+				Value *Custom `env:"VALUE"`
+			}{},
+			get: func(out any) any {
+				return *out.(*struct {
+					Value *Custom `env:"VALUE"`
+				}).Value
+			},
+			want: Custom(42),
+		}, {
+			name: "**Custom",
+			out: &struct {
+				Value **Custom `env:"VALUE"`
+			}{},
+			get: func(out any) any {
+				return **out.(*struct {
+					Value **Custom `env:"VALUE"`
+				}).Value
+			},
+			want: Custom(42),
+		}, {
+			name: "*CustomText",
+			out: &struct {
+				Value *CustomText `env:"VALUE"`
+			}{},
+			get: func(out any) any {
+				return *out.(*struct {
+					Value *CustomText `env:"VALUE"`
+				}).Value
+			},
+			want: CustomText(42),
+		}, {
+			name: "**CustomText",
+			out: &struct {
+				Value **CustomText `env:"VALUE"`
+			}{},
+			get: func(out any) any {
+				return **out.(*struct {
+					Value **CustomText `env:"VALUE"`
+				}).Value
+			},
+			want: CustomText(42),
+		}, {
+			name: "[]*Custom",
+			out: &struct {
+				Value []*Custom `env:"VALUE"`
+			}{},
+			get: func(out any) any {
+				return *out.(*struct {
+					Value []*Custom `env:"VALUE"`
+				}).Value[0]
+			},
+			want: Custom(42),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("VALUE", "42")
+
+			err := env.Unmarshal(tc.out)
+			if err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := tc.get(tc.out), tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_PointerParseError_LeavesPointerNil(t *testing.T) {
+	type PointerEnv struct {
+		Custom **Custom `env:"CUSTOM"`
+		Int    ***int   `env:"INT"`
+	}
+	testCases := []struct {
+		name string
+		key  string
+	}{
+		{name: "Unmarshaler", key: "CUSTOM"},
+		{name: "Primitive", key: "INT"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(tc.key, "not_a_number")
+
+			var out PointerEnv
+			err := env.Unmarshal(&out)
+
+			if got, want := err, env.ErrParse; !errors.Is(got, want) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if out.Custom != nil || out.Int != nil {
+				t.Errorf("Unmarshal(%s): got allocated pointer, want nil", tc.name)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_UnexportedTaggedField_IsIgnored(t *testing.T) {
+	type UnexportedEnv struct {
+		Name     string `env:"NAME"`
+		internal string `env:"INTERNAL,required"`
+		Port     int    `env:"PORT"`
+	}
+	setenv(t, `
+		NAME=example
+		INTERNAL=secret
+		PORT=8080
+	`)
+
+	var out UnexportedEnv
+	err := env.Unmarshal(&out)
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := UnexportedEnv{
+		Name: "example",
+		Port: 8080,
+	}
+	if got := out; !cmp.Equal(got, want, cmp.AllowUnexported(UnexportedEnv{})) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+type Common struct {
+	Region string `env:"REGION,required"`
+	Zone   string `env:"ZONE"`
+}
+
+func TestUnmarshal_EmbeddedStructs(t *testing.T) {
+	type EmbeddedValueEnv struct {
+		Common
+		Name string `env:"NAME"`
+	}
+	type EmbeddedPointerEnv struct {
+		*Common
+		Name string `env:"NAME"`
+	}
+
+	testCases := []struct {
+		name        string
+		environment string
+		out         any
+		want        any
+	}{
+		{
+			name: "Embedded value",
+			environment: `
+				REGION=us-east-1
+				NAME=example
+			`,
+			out: &EmbeddedValueEnv{},
+			want: &EmbeddedValueEnv{
+				Common: Common{Region: "us-east-1"},
+				Name:   "example",
+			},
+		}, {
+			name: "Embedded pointer all unset",
+			environment: `
+				NAME=example
+			`,
+			out: &EmbeddedPointerEnv{},
+			want: &EmbeddedPointerEnv{
+				Name: "example",
+			},
+		}, {
+			name: "Embedded pointer partially set",
+			environment: `
+				REGION=us-east-1
+				NAME=example
+			`,
+			out: &EmbeddedPointerEnv{},
+			want: &EmbeddedPointerEnv{
+				Common: &Common{Region: "us-east-1"},
+				Name:   "example",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := env.SealedEnvironment{}
+			setEnvironment(env.Environment(sut), tc.environment)
+
+			err := sut.Unmarshal(tc.out)
+			if err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := tc.out, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_EmbeddedPointerPartiallySet_EnforcesRequired(t *testing.T) {
+	type EmbeddedPointerEnv struct {
+		*Common
+	}
+	sut := env.SealedEnvironment{"ZONE": "a"}
+
+	var out EmbeddedPointerEnv
+	err := sut.Unmarshal(&out)
+
+	if got, want := err, env.ErrRequirement; !errors.Is(got, want) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+	if out.Common != nil {
+		t.Errorf("Unmarshal(): got allocated pointer '%v', want nil", out.Common)
+	}
+}
+
+func TestUnmarshal_NonEmpty(t *testing.T) {
+	type NonEmptyEnv struct {
+		Required string `env:"REQUIRED,required"`
+		NonEmpty string `env:"NON_EMPTY,required,nonempty"`
+		Optional string `env:"OPTIONAL,nonempty"`
+	}
+
+	testCases := []struct {
+		name    string
+		env     env.SealedEnvironment
+		opts    []env.UnmarshalOption
+		wantErr error
+	}{
+		{
+			name: "Required empty without option",
+			env:  env.SealedEnvironment{"REQUIRED": "", "NON_EMPTY": "value"},
+		}, {
+			name:    "Nonempty tag with empty value",
+			env:     env.SealedEnvironment{"REQUIRED": "", "NON_EMPTY": ""},
+			wantErr: env.ErrRequirement,
+		}, {
+			name:    "RequireNonEmpty with empty value",
+			env:     env.SealedEnvironment{"REQUIRED": "", "NON_EMPTY": "value"},
+			opts:    []env.UnmarshalOption{env.RequireNonEmpty()},
+			wantErr: env.ErrRequirement,
+		}, {
+			name: "Optional empty value",
+			env:  env.SealedEnvironment{"REQUIRED": "value", "NON_EMPTY": "value", "OPTIONAL": ""},
+			opts: []env.UnmarshalOption{env.RequireNonEmpty()},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out NonEmptyEnv
+			err := tc.env.Unmarshal(&out, tc.opts...)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_NonEmptyCollectionWithEmptyEntry(t *testing.T) {
+	type NonEmptyEnv struct {
+		Paths  []string          `env:"PATHS,required,nonempty"`
+		Ports  []int             `env:"PORTS,required,nonempty"`
+		Labels map[string]string `env:"LABELS,required,nonempty"`
+	}
+
+	testCases := []struct {
+		name    string
+		env     env.SealedEnvironment
+		want    NonEmptyEnv
+		wantErr error
+	}{
+		{
+			name: "Empty string entries",
+			env:  env.SealedEnvironment{"PATHS": "a,,b", "PORTS": "80", "LABELS": "team=,tier=1"},
+			want: NonEmptyEnv{
+				Paths:  []string{"a", "", "b"},
+				Ports:  []int{80},
+				Labels: map[string]string{"team": "", "tier": "1"},
+			},
+		}, {
+			name:    "Empty integer entry",
+			env:     env.SealedEnvironment{"PATHS": "a", "PORTS": "80,,443", "LABELS": "tier=1"},
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out NonEmptyEnv
+			err := tc.env.Unmarshal(&out)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if errors.Is(err, env.ErrRequirement) {
+				t.Errorf("Unmarshal(%s): got requirement error '%v' for a set variable", tc.name, err)
+			}
+			if tc.wantErr == nil && !cmp.Equal(out, tc.want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, out, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_CSV(t *testing.T) {
+	type CSVEnv struct {
+		Tags []string `env:"TAGS,csv"`
+	}
+
+	testCases := []struct {
+		name    string
+		value   string
+		opts    []env.UnmarshalOption
+		want    []string
+		wantErr error
+	}{
+		{
+			name:  "Quoted separator",
+			value: `a,"b,c",d`,
+			want:  []string{"a", "b,c", "d"},
+		}, {
+			name:  "Escaped quote",
+			value: `a,"say ""hi"""`,
+			want:  []string{"a", `say "hi"`},
+		}, {
+			name:  "Custom separator",
+			value: `a;"b;c"`,
+			opts:  []env.UnmarshalOption{env.Separator(";")},
+			want:  []string{"a", "b;c"},
+		}, {
+			name:    "Unterminated quote",
+			value:   `a,"b`,
+			wantErr: env.ErrParse,
+		}, {
+			name:    "Multi-character separator",
+			value:   `a::b`,
+			opts:    []env.UnmarshalOption{env.Separator("::")},
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := env.SealedEnvironment{"TAGS": env.Value(tc.value)}
+
+			var out CSVEnv
+			err := sut.Unmarshal(&out, tc.opts...)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := out.Tags, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Escape(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		opts  []env.UnmarshalOption
+		want  []string
+	}{
+		{
+			name:  "Escaped separator at start",
+			value: `\,a,b`,
+			want:  []string{",a", "b"},
+		}, {
+			name:  "Escaped separator in middle",
+			value: `A\,B,C`,
+			want:  []string{"A,B", "C"},
+		}, {
+			name:  "Escaped separator at end",
+			value: `a,b\,`,
+			want:  []string{"a", "b,"},
+		}, {
+			name:  "Escaped backslash",
+			value: `a\\,b`,
+			want:  []string{`a\`, "b"},
+		}, {
+			name:  "Other backslashes kept",
+			value: `C:\dir,b`,
+			want:  []string{`C:\dir`, "b"},
+		}, {
+			name:  "Multi-character separator",
+			value: `a\::b::c`,
+			opts:  []env.UnmarshalOption{env.Separator("::")},
+			want:  []string{"a::b", "c"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := env.SealedEnvironment{"TAGS": env.Value(tc.value)}
+
+			var out struct {
+				Tags []string `env:"TAGS,escape"`
+			}
+			if err := sut.Unmarshal(&out, tc.opts...); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := out.Tags, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_MapSeparatorInValue(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		opts  []env.UnmarshalOption
+	}{
+		{
+			name:  "CSV",
+			value: `a=1,"b=2,3"`,
+			opts:  []env.UnmarshalOption{env.CSV()},
+		}, {
+			name:  "Escape",
+			value: `a=1,b=2\,3`,
+			opts:  []env.UnmarshalOption{env.Escape()},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := env.SealedEnvironment{"LABELS": env.Value(tc.value)}
+
+			var out struct {
+				Labels map[string]string `env:"LABELS"`
+			}
+			if err := sut.Unmarshal(&out, tc.opts...); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			want := map[string]string{"a": "1", "b": "2,3"}
+			if got := out.Labels; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_EscapeOption_AppliesToEveryField(t *testing.T) {
+	var out struct {
+		Tags []string `env:"TAGS"`
+	}
+
+	err := env.SealedEnvironment{"TAGS": `a\,b,c`}.Unmarshal(&out, env.Escape())
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := out.Tags, []string{"a,b", "c"}; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_EscapeWithCSV_ReturnsError(t *testing.T) {
+	var out struct {
+		Tags []string `env:"TAGS,csv,escape"`
+	}
+
+	err := env.SealedEnvironment{}.Unmarshal(&out)
+
+	if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_WithoutCSV_SplitsNaively(t *testing.T) {
+	type NaiveEnv struct {
+		Tags []string `env:"TAGS"`
+	}
+	sut := env.SealedEnvironment{"TAGS": `a,"b,c"`}
+
+	var out NaiveEnv
+	if err := sut.Unmarshal(&out); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := out.Tags, []string{"a", `"b`, `c"`}; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_NameFunc(t *testing.T) {
+	type NameEnv struct {
+		ProjectName string
+		Tagged      string `env:"TAGGED_KEY"`
+		Upstreams   []struct {
+			Host string
+		} `env:"UPSTREAM"`
+	}
+	sut := env.SealedEnvironment{
+		"ProjectName":     "example",
+		"PROJECT_NAME":    "ignored",
+		"TAGGED_KEY":      "tagged",
+		"UPSTREAM_0_Host": "localhost",
+	}
+
+	var out NameEnv
+	err := sut.Unmarshal(&out, env.NameFunc(func(name string) string { return name }))
+	if err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := out.ProjectName, "example"; got != want {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+	if got, want := out.Tagged, "tagged"; got != want {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+	if got, want := len(out.Upstreams), 1; got != want {
+		t.Fatalf("Unmarshal(): got %v upstreams, want %v", got, want)
+	}
+	if got, want := out.Upstreams[0].Host, "localhost"; got != want {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_Base(t *testing.T) {
+	type BaseEnv struct {
+		Color  uint32  `env:"COLOR,base=16"`
+		Mode   int     `env:"MODE,base=8"`
+		Flags  []uint8 `env:"FLAGS,base=2"`
+		Number int     `env:"NUMBER"`
+	}
+	sut := env.SealedEnvironment{
+		"COLOR":  "ff00ff",
+		"MODE":   "755",
+		"FLAGS":  "101,11",
+		"NUMBER": "0x10",
+	}
+
+	var out BaseEnv
+	if err := sut.Unmarshal(&out); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := BaseEnv{Color: 0xff00ff, Mode: 0755, Flags: []uint8{5, 3}, Number: 16}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_DecimalOnly(t *testing.T) {
+	type DecimalEnv struct {
+		Count int  `env:"COUNT"`
+		Mode  uint `env:"MODE,base=8"`
+	}
+	sut := env.SealedEnvironment{
+		"COUNT": "010",
+		"MODE":  "010",
+	}
+
+	var out DecimalEnv
+	if err := sut.Unmarshal(&out, env.DecimalOnly()); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := DecimalEnv{Count: 10, Mode: 8}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_DecimalOnly_PrefixedValue_ReturnsParseError(t *testing.T) {
+	var out struct {
+		Count int `env:"COUNT"`
+	}
+
+	err := env.SealedEnvironment{"COUNT": "0x10"}.Unmarshal(&out, env.DecimalOnly())
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_TimeZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	type TimeZoneEnv struct {
+		Time time.Time `env:"TIME,tz=America/New_York"`
+	}
+
+	testCases := []struct {
+		name  string
+		value string
+		want  time.Time
+	}{
+		{
+			name:  "Date in standard time",
+			value: "2024-01-15",
+			want:  time.Date(2024, time.January, 15, 0, 0, 0, 0, newYork),
+		}, {
+			name:  "Date in daylight saving time",
+			value: "2024-07-15",
+			want:  time.Date(2024, time.July, 15, 0, 0, 0, 0, newYork),
+		}, {
+			name:  "Before spring forward",
+			value: "2024-03-10 01:59:59",
+			want:  time.Date(2024, time.March, 10, 6, 59, 59, 0, time.UTC),
+		}, {
+			name:  "After spring forward",
+			value: "2024-03-10 03:00:00",
+			want:  time.Date(2024, time.March, 10, 7, 0, 0, 0, time.UTC),
+		}, {
+			name:  "After fall back",
+			value: "2024-11-03 02:00:00",
+			want:  time.Date(2024, time.November, 3, 7, 0, 0, 0, time.UTC),
+		}, {
+			name:  "Explicit offset is preserved",
+			value: "2024-01-15T00:00:00Z",
+			want:  time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sut := env.SealedEnvironment{"TIME": env.Value(tc.value)}
+
+			var out TimeZoneEnv
+			if err := sut.Unmarshal(&out); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := out.Time, tc.want; !got.Equal(want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_TimeZone_InvalidOption_ReturnsError(t *testing.T) {
+	testCases := []struct {
+		name string
+		out  any
+	}{
+		{
+			name: "Unknown location",
+			out: &struct {
+				Value time.Time `env:"VALUE,tz=Not/A_Zone"`
+			}{},
+		}, {
+			name: "Empty location",
+			out: &struct {
+				Value time.Time `env:"VALUE,tz="`
+			}{},
+		}, {
+			name: "Non-time field",
+			out: &struct {
+				Value string `env:"VALUE,tz=UTC"`
+			}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := env.SealedEnvironment{}.Unmarshal(tc.out)
+
+			if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+var errInvalidConfig = errors.New("invalid config")
+
+type ValidatedPort int
+
+func (p ValidatedPort) Validate() error {
+	if p == 0 {
+		return errInvalidConfig
+	}
+	return nil
+}
+
+type ValidatedUpstream struct {
+	Host string `env:"HOST"`
+}
+
+func (u *ValidatedUpstream) Validate() error {
+	if u.Host == "" {
+		return errInvalidConfig
+	}
+	return nil
+}
+
+type ValidatedEnv struct {
+	Name      string              `env:"NAME"`
+	Port      ValidatedPort       `env:"PORT"`
+	Upstreams []ValidatedUpstream `env:"UPSTREAM"`
+}
+
+func (e ValidatedEnv) Validate() error {
+	if e.Name == "invalid" {
+		return errInvalidConfig
+	}
+	return nil
+}
+
+func TestUnmarshal_Validator(t *testing.T) {
+	testCases := []struct {
+		name    string
+		env     env.SealedEnvironment
+		wantErr error
+		wantKey string
+	}{
+		{
+			name: "Valid",
+			env:  env.SealedEnvironment{"NAME": "example", "PORT": "80", "UPSTREAM_0_HOST": "localhost"},
+		}, {
+			name:    "Invalid top-level struct",
+			env:     env.SealedEnvironment{"NAME": "invalid"},
+			wantErr: errInvalidConfig,
+			wantKey: "",
+		}, {
+			name:    "Invalid field",
+			env:     env.SealedEnvironment{"NAME": "example", "PORT": "0"},
+			wantErr: errInvalidConfig,
+			wantKey: "PORT",
+		}, {
+			name:    "Invalid nested struct",
+			env:     env.SealedEnvironment{"NAME": "example", "UPSTREAM_0_HOST": ""},
+			wantErr: errInvalidConfig,
+			wantKey: "UPSTREAM_0",
+		}, {
+			name: "Unset field is not validated",
+			env:  env.SealedEnvironment{"NAME": "example"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out ValidatedEnv
+			err := tc.env.Unmarshal(&out)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if err == nil {
+				return
+			}
+			var validationErr *env.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Unmarshal(%s): expected ValidationError, got %T", tc.name, err)
+			}
+			if got, want := validationErr.Key, tc.wantKey; got != want {
+				t.Errorf("Unmarshal(%s): got key '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Validator_NotCalledOnDecodeError(t *testing.T) {
+	sut := env.SealedEnvironment{"NAME": "invalid", "PORT": "not-a-number"}
+
+	var out ValidatedEnv
+	err := sut.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+	if errors.Is(err, errInvalidConfig) {
+		t.Errorf("Unmarshal(): got validation error '%v', want none", err)
+	}
+}
+
+func TestUnmarshal_Length(t *testing.T) {
+	type Upstream struct {
+		Host string `env:"HOST"`
+	}
+	type LengthEnv struct {
+		Paths     []string       `env:"PATHS,required,minlen=1"`
+		Tags      []string       `env:"TAGS,maxlen=2"`
+		Labels    map[string]int `env:"LABELS,minlen=1,maxlen=1"`
+		Upstreams []Upstream     `env:"UPSTREAM,maxlen=1"`
+	}
+
+	testCases := []struct {
+		name    string
+		env     env.SealedEnvironment
+		wantErr error
+	}{
+		{
+			name: "Within bounds",
+			env:  env.SealedEnvironment{"PATHS": "/bin", "TAGS": "a,b", "LABELS": "a=1", "UPSTREAM_0_HOST": "a"},
+		}, {
+			name: "Unset optional fields",
+			env:  env.SealedEnvironment{"PATHS": "/bin"},
+		}, {
+			name:    "Required slice with no elements",
+			env:     env.SealedEnvironment{"PATHS": ""},
+			wantErr: env.ErrValidation,
+		}, {
+			name:    "Too many slice elements",
+			env:     env.SealedEnvironment{"PATHS": "/bin", "TAGS": "a,b,c"},
+			wantErr: env.ErrValidation,
+		}, {
+			name:    "Empty map",
+			env:     env.SealedEnvironment{"PATHS": "/bin", "LABELS": ""},
+			wantErr: env.ErrValidation,
+		}, {
+			name:    "Too many map entries",
+			env:     env.SealedEnvironment{"PATHS": "/bin", "LABELS": "a=1,b=2"},
+			wantErr: env.ErrValidation,
+		}, {
+			name:    "Too many struct slice elements",
+			env:     env.SealedEnvironment{"PATHS": "/bin", "UPSTREAM_0_HOST": "a", "UPSTREAM_1_HOST": "b"},
+			wantErr: env.ErrValidation,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out LengthEnv
+			err := tc.env.Unmarshal(&out)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Length_ErrorNamesKeyAndConstraint(t *testing.T) {
+	type LengthEnv struct {
+		Paths []string `env:"PATHS,required,minlen=1"`
+	}
+	sut := env.SealedEnvironment{"PATHS": ""}
+
+	var out LengthEnv
+	err := sut.Unmarshal(&out)
+
+	want := "env: invalid value for env variable 'PATHS': must have at least 1 elements, got 0"
+	if err == nil || err.Error() != want {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", err, want)
+	}
+}
+
+func TestUnmarshal_ValueField_AppliesStringOptions(t *testing.T) {
+	type ValueEnv struct {
+		Level env.Value `env:"LEVEL,trim,upper,oneof=DEBUG INFO"`
+	}
+	sut := env.SealedEnvironment{"LEVEL": " info "}
+
+	var out ValueEnv
+	if err := sut.Unmarshal(&out); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := out.Level, env.Value("INFO"); got != want {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_WithTracer(t *testing.T) {
+	type TraceEnv struct {
+		Name     string `env:"NAME"`
+		Missing  string `env:"MISSING"`
+		Token    string `env:"TOKEN,secret"`
+		Disabled string `env:"DISABLED,feature=disabled"`
+	}
+	type trace struct {
+		Key   string
+		Found bool
+		Value string
+	}
+	sut := env.SealedEnvironment{"NAME": "example", "TOKEN": "hunter2", "DISABLED": "value"}
+
+	var got []trace
+	tracer := env.WithTracer(func(key string, found bool, value string) {
+		got = append(got, trace{Key: key, Found: found, Value: value})
+	})
+	var out TraceEnv
+	if err := sut.Unmarshal(&out, tracer); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := []trace{
+		{Key: "NAME", Found: true, Value: "example"},
+		{Key: "MISSING", Found: false, Value: ""},
+		{Key: "TOKEN", Found: true, Value: "***"},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestUnmarshal_Presence(t *testing.T) {
+	type PresenceEnv struct {
+		Debug   bool  `env:"DEBUG,presence"`
+		Verbose *bool `env:"VERBOSE,presence"`
+	}
+
+	testCases := []struct {
+		name string
+		env  env.SealedEnvironment
+		out  PresenceEnv
+		want PresenceEnv
+	}{
+		{
+			name: "Set to empty",
+			env:  env.SealedEnvironment{"DEBUG": "", "VERBOSE": ""},
+			want: PresenceEnv{Debug: true, Verbose: ptr(true)},
+		}, {
+			name: "Set to false",
+			env:  env.SealedEnvironment{"DEBUG": "false"},
+			want: PresenceEnv{Debug: true, Verbose: ptr(false)},
+		}, {
+			name: "Unset overrides default",
+			env:  env.SealedEnvironment{},
+			out:  PresenceEnv{Debug: true},
+			want: PresenceEnv{Debug: false, Verbose: ptr(false)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := tc.out
+			if err := tc.env.Unmarshal(&out); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := out, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Inline(t *testing.T) {
+	type Database struct {
+		Host string `env:"host"`
+		Port int    `env:"port"`
+	}
+	type InlineEnv struct {
+		DotEnv  Database  `env:"DOTENV_DB,inline=dotenv"`
+		JSON    *Database `env:"JSON_DB,inline=json"`
+		Default Database  `env:"DEFAULT_DB,inline=json"`
+	}
+
+	testCases := []struct {
+		name string
+		env  env.SealedEnvironment
+		out  InlineEnv
+		want InlineEnv
+	}{
+		{
+			name: "Dotenv format",
+			env:  env.SealedEnvironment{"DOTENV_DB": "host=localhost port=5432"},
+			want: InlineEnv{DotEnv: Database{Host: "localhost", Port: 5432}},
+		}, {
+			name: "JSON format",
+			env:  env.SealedEnvironment{"JSON_DB": `{"host": "localhost", "port": 5432}`},
+			want: InlineEnv{JSON: &Database{Host: "localhost", Port: 5432}},
+		}, {
+			name: "Partial value keeps defaults",
+			env:  env.SealedEnvironment{"DEFAULT_DB": `{"port": 3306}`},
+			out:  InlineEnv{Default: Database{Host: "localhost", Port: 5432}},
+			want: InlineEnv{Default: Database{Host: "localhost", Port: 3306}},
+		}, {
+			name: "Unset leaves pointer nil",
+			env:  env.SealedEnvironment{},
+			want: InlineEnv{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := tc.out
+			if err := tc.env.Unmarshal(&out); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := out, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_InlineMalformed_ReturnsParseError(t *testing.T) {
+	type Database struct {
+		Port int `env:"port"`
+	}
+
+	testCases := []struct {
+		name string
+		env  env.SealedEnvironment
+	}{
+		{
+			name: "Malformed dotenv",
+			env:  env.SealedEnvironment{"DOTENV_DB": "port"},
+		}, {
+			name: "Malformed JSON",
+			env:  env.SealedEnvironment{"JSON_DB": `{"port":`},
+		}, {
+			name: "Malformed entry",
+			env:  env.SealedEnvironment{"JSON_DB": `{"port": "abc"}`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out struct {
+				DotEnv Database  `env:"DOTENV_DB,inline=dotenv"`
+				JSON   *Database `env:"JSON_DB,inline=json"`
+			}
+
+			err := tc.env.Unmarshal(&out)
+
+			if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			if out.JSON != nil {
+				t.Errorf("Unmarshal(%s): got '%v', want nil", tc.name, out.JSON)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_InlineRequired_ReturnsRequirementError(t *testing.T) {
+	var out struct {
+		Database struct {
+			Host string `env:"host"`
+		} `env:"DATABASE,required,inline=dotenv"`
+	}
+
+	err := env.SealedEnvironment{}.Unmarshal(&out)
+
+	if got, want := err, env.ErrRequirement; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_Inline_InvalidOption_ReturnsError(t *testing.T) {
+	testCases := []struct {
+		name string
+		out  any
+	}{
+		{
+			name: "Unknown format",
+			out: &struct {
+				Value struct{ Host string } `env:"VALUE,inline=yaml"`
+			}{},
+		}, {
+			name: "Non-struct field",
+			out: &struct {
+				Value string `env:"VALUE,inline=json"`
+			}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := env.SealedEnvironment{}.Unmarshal(tc.out)
+
+			if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Concat(t *testing.T) {
+	type ConcatEnv struct {
+		Cert   string   `env:"CERT,concat=CERT_2,concat=CERT_3"`
+		Joined []string `env:"JOINED,sep=;,concat=JOINED_2,join=;"`
+	}
+
+	testCases := []struct {
+		name string
+		env  env.SealedEnvironment
+		want ConcatEnv
+	}{
+		{
+			name: "All parts set",
+			env:  env.SealedEnvironment{"CERT": "a", "CERT_2": "b", "CERT_3": "c"},
+			want: ConcatEnv{Cert: "abc"},
+		}, {
+			name: "Missing parts are empty",
+			env:  env.SealedEnvironment{"CERT": "a", "CERT_3": "c"},
+			want: ConcatEnv{Cert: "ac"},
+		}, {
+			name: "Only part set",
+			env:  env.SealedEnvironment{"CERT_2": "b"},
+			want: ConcatEnv{Cert: "b"},
+		}, {
+			name: "Join string",
+			env:  env.SealedEnvironment{"JOINED": "a;b", "JOINED_2": "c"},
+			want: ConcatEnv{Joined: []string{"a", "b", "c"}},
+		}, {
+			name: "Unset",
+			env:  env.SealedEnvironment{},
+			want: ConcatEnv{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out ConcatEnv
+			if err := tc.env.Unmarshal(&out); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if got, want := out, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_ConcatRequired_ReturnsMissingPart(t *testing.T) {
+	testCases := []struct {
+		name    string
+		env     env.SealedEnvironment
+		wantKey string
+	}{
+		{
+			name:    "Missing primary key",
+			env:     env.SealedEnvironment{"CERT_2": "b"},
+			wantKey: "CERT",
+		}, {
+			name:    "Missing part",
+			env:     env.SealedEnvironment{"CERT": "a"},
+			wantKey: "CERT_2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out struct {
+				Cert string `env:"CERT,required,concat=CERT_2"`
+			}
+
+			err := tc.env.Unmarshal(&out)
+
+			var requirementErr *env.RequirementError
+			if !errors.As(err, &requirementErr) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want RequirementError", tc.name, err)
+			}
+			if got, want := requirementErr.Key, tc.wantKey; got != want {
+				t.Errorf("Unmarshal(%s): got key '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_JoinWithoutConcat_ReturnsError(t *testing.T) {
+	var out struct {
+		Value string `env:"VALUE,join=-"`
+	}
+
+	err := env.SealedEnvironment{}.Unmarshal(&out)
+
+	if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+type Region string
+
+type Count int
+
+type Blob []byte
+
+// MismatchedUnmarshaler has an UnmarshalEnv method with the wrong signature, so
+// it does not implement env.Unmarshaler.
+type MismatchedUnmarshaler string
+
+func (m *MismatchedUnmarshaler) UnmarshalEnv(value string) error {
+	*m = "unexpected"
+	return nil
+}
+
+func TestUnmarshal_NamedTypes(t *testing.T) {
+	type NamedEnv struct {
+		Region     Region                `env:"REGION"`
+		Count      Count                 `env:"COUNT"`
+		Blob       Blob                  `env:"BLOB"`
+		Regions    []Region              `env:"REGIONS"`
+		Mismatched MismatchedUnmarshaler `env:"MISMATCHED"`
+	}
+	sut := env.SealedEnvironment{
+		"REGION":     "us-east-1",
+		"COUNT":      "42",
+		"BLOB":       "1,2,255",
+		"REGIONS":    "us-east-1,eu-west-1",
+		"MISMATCHED": "value",
+	}
+
+	var got NamedEnv
+	if err := sut.Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := NamedEnv{
+		Region:     "us-east-1",
+		Count:      42,
+		Blob:       Blob{1, 2, 255},
+		Regions:    []Region{"us-east-1", "eu-west-1"},
+		Mismatched: "value",
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestUnmarshal_NamedByteSliceOutOfRange_ReturnsParseError(t *testing.T) {
+	var out struct {
+		Blob Blob `env:"BLOB"`
+	}
+
+	err := env.SealedEnvironment{"BLOB": "256"}.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_Unique(t *testing.T) {
+	type UniqueEnv struct {
+		Features []string `env:"FEATURES,unique"`
+		Ports    []int    `env:"PORTS,unique,maxlen=2"`
+		Weights  []*int   `env:"WEIGHTS,unique"`
+	}
+	sut := env.SealedEnvironment{
+		"FEATURES": "b,a,b,c,a",
+		"PORTS":    "443,80,443,80",
+		"WEIGHTS":  "1,2,1",
+	}
+
+	var got UniqueEnv
+	if err := sut.Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := UniqueEnv{
+		Features: []string{"b", "a", "c"},
+		Ports:    []int{443, 80},
+		Weights:  []*int{ptr(1), ptr(2)},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestUnmarshal_Unique_InvalidOption_ReturnsError(t *testing.T) {
+	testCases := []struct {
+		name string
+		out  any
+	}{
+		{
+			name: "Non-slice field",
+			out: &struct {
+				Value string `env:"VALUE,unique"`
+			}{},
+		}, {
+			name: "Array field",
+			out: &struct {
+				Value [2]string `env:"VALUE,unique"`
+			}{},
+		}, {
+			name: "Non-comparable elements",
+			out: &struct {
+				Value [][]string `env:"VALUE,unique"`
+			}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := env.SealedEnvironment{}.Unmarshal(tc.out)
+
+			if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_FileFallback(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatalf("WriteFile(): unexpected error: %v", err)
+		}
+		return path
+	}
+
+	type FileEnv struct {
+		Password string `env:"PASSWORD,required"`
+		Token    string `env:"TOKEN"`
+	}
+
+	testCases := []struct {
+		name string
+		env  env.SealedEnvironment
+		want FileEnv
+	}{
+		{
+			name: "Reads file when key is unset",
+			env:  env.SealedEnvironment{"PASSWORD_FILE": env.Value(write("password", "hunter2\n"))},
+			want: FileEnv{Password: "hunter2"},
+		}, {
+			name: "Trims only one trailing newline",
+			env:  env.SealedEnvironment{"PASSWORD_FILE": env.Value(write("crlf", "line1\nline2\r\n\r\n"))},
+			want: FileEnv{Password: "line1\nline2\r\n"},
+		}, {
+			name: "Key takes precedence",
+			env: env.SealedEnvironment{
+				"PASSWORD":      "from-env",
+				"PASSWORD_FILE": env.Value(write("ignored", "from-file")),
+			},
+			want: FileEnv{Password: "from-env"},
+		}, {
+			name: "Only key set",
+			env:  env.SealedEnvironment{"PASSWORD": "from-env"},
+			want: FileEnv{Password: "from-env"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got FileEnv
+			if err := tc.env.Unmarshal(&got, env.FileFallback()); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_FileFallbackUnreadable_ReturnsParseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing")
+	sut := env.SealedEnvironment{"PASSWORD_FILE": env.Value(path)}
+
+	var out struct {
+		Password string `env:"PASSWORD"`
+	}
+	err := sut.Unmarshal(&out, env.FileFallback())
+
+	var parseErr *env.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Unmarshal(): got err '%v', want ParseError", err)
+	}
+	if got, want := parseErr.Key, "PASSWORD_FILE"; got != want {
+		t.Errorf("Unmarshal(): got key '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_WithoutFileFallback_IgnoresFileKey(t *testing.T) {
+	sut := env.SealedEnvironment{"PASSWORD_FILE": "/does/not/exist"}
+
+	var out struct {
+		Password string `env:"PASSWORD,required"`
+	}
+	err := sut.Unmarshal(&out)
+
+	if got, want := err, env.ErrRequirement; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_UnsetOptionalPointers_StayNil(t *testing.T) {
+	type Optional struct {
+		Zone string `env:"ZONE"`
+	}
+	type PointerEnv struct {
+		*Common
+		String      *string           `env:"STRING"`
+		Pointers    ***int            `env:"POINTERS"`
+		Slice       *[]string         `env:"SLICE"`
+		Map         *map[string]int   `env:"MAP"`
+		Array       *[2]int           `env:"ARRAY"`
+		Duration    *time.Duration    `env:"DURATION"`
+		Time        *time.Time        `env:"TIME"`
+		BigInt      *big.Int          `env:"BIG_INT"`
+		BigFloat    *big.Float        `env:"BIG_FLOAT"`
+		Regexp      *regexp.Regexp    `env:"REGEXP"`
+		Unmarshaler *Custom           `env:"UNMARSHALER"`
+		Text        *CustomText       `env:"TEXT"`
+		Value       *env.Value        `env:"VALUE"`
+		Inline      *Optional         `env:"INLINE,inline=json"`
+		Structs     *[]Optional       `env:"STRUCTS"`
+		Trimmed     *string           `env:"TRIMMED,trim,lower,oneof=a b"`
+		Bounded     *int              `env:"BOUNDED,min=1,max=2"`
+		Concat      *string           `env:"CONCAT,concat=CONCAT_2"`
+		Nested      **[]*int          `env:"NESTED"`
+		Labels      map[string]*int   `env:"LABELS"`
+		Elements    []*int            `env:"ELEMENTS"`
+		Keyed       *map[string]*bool `env:"KEYED"`
+	}
+	sut := env.SealedEnvironment{"UNRELATED": "value"}
+
+	var got PointerEnv
+	if err := sut.Unmarshal(&got, env.FileFallback()); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	rv := reflect.ValueOf(got)
+	for i := 0; i < rv.NumField(); i++ {
+		if field := rv.Field(i); !field.IsZero() {
+			t.Errorf("Unmarshal(): got '%v' for unset field %s, want zero", field, rv.Type().Field(i).Name)
+		}
+	}
+}
+
+func TestUnmarshal_UnsetOptionalPointers_KeepExistingValues(t *testing.T) {
+	existing := "existing"
+	var got struct {
+		String *string `env:"STRING"`
+	}
+	got.String = &existing
+
+	if err := (env.SealedEnvironment{}).Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := got.String, &existing; got != want {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_IgnoredField_IsUntouched(t *testing.T) {
+	type IgnoredEnv struct {
+		Name    string `env:"NAME"`
+		Ignored string `env:"-"`
+		Dash    string `env:"-,"`
+		Cache   struct {
+			Size int `env:"SIZE,required"`
+		} `env:"-"`
+	}
+	sut := env.SealedEnvironment{
+		"NAME":    "example",
+		"-":       "dash",
+		"IGNORED": "environment",
+	}
+
+	got := IgnoredEnv{Ignored: "existing"}
+	if err := sut.Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := IgnoredEnv{Name: "example", Ignored: "existing", Dash: "dash"}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+// Hex is an int whose text form is hexadecimal, but whose env form is decimal.
+type Hex int
+
+func (h *Hex) UnmarshalEnv(b []byte) error {
+	v, err := strconv.ParseInt(string(b), 10, 0)
+	*h = Hex(v)
+	return err
+}
+
+func (h *Hex) UnmarshalText(text []byte) error {
+	v, err := strconv.ParseInt(string(text), 16, 0)
+	*h = Hex(v)
+	return err
+}
+
+func TestUnmarshal_StringOption(t *testing.T) {
+	type StringEnv struct {
+		Default Hex       `env:"VALUE"`
+		Text    Hex       `env:"VALUE,string"`
+		Texts   []Hex     `env:"VALUES,string"`
+		Time    time.Time `env:"TIME,string"`
+		Name    string    `env:"NAME,string"`
+	}
+	sut := env.SealedEnvironment{
+		"VALUE":  "10",
+		"VALUES": "10,ff",
+		"TIME":   "2024-01-02T03:04:05Z",
+		"NAME":   "example",
+	}
+
+	var got StringEnv
+	if err := sut.Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := StringEnv{
+		Default: 10,
+		Text:    16,
+		Texts:   []Hex{16, 255},
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Name:    "example",
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): mismatch (-want +got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestUnmarshal_StringOption_UsesOnlyTextForm(t *testing.T) {
+	var out struct {
+		Time time.Time `env:"TIME,string"`
+	}
+
+	err := env.SealedEnvironment{"TIME": "2024-01-02"}.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_StringOption_InvalidType_ReturnsError(t *testing.T) {
+	var out struct {
+		Count int `env:"COUNT,string"`
+	}
+
+	err := env.SealedEnvironment{}.Unmarshal(&out)
+
+	if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_TagDecoder(t *testing.T) {
+	reverse := func(value string, out any) error {
+		runes := []rune(value)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		*out.(*string) = string(runes)
+		return nil
+	}
+	sealed := env.SealedEnvironment{"NAME": "olleh"}
+	var out struct {
+		Name  string `env:"NAME,reverse"`
+		Unset string `env:"UNSET,reverse"`
+	}
+	out.Unset = "default"
+
+	if err := sealed.Unmarshal(&out, env.WithTagDecoder("reverse", reverse)); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := out.Name, "hello"; got != want {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+	if got, want := out.Unset, "default"; got != want {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_TagDecoderFails_ReturnsParseError(t *testing.T) {
+	fail := func(string, any) error {
+		return errors.New("bad value")
+	}
+	sealed := env.SealedEnvironment{"NAME": "value"}
+	var out struct {
+		Name string `env:"NAME,custom"`
+	}
+	out.Name = "default"
+
+	err := sealed.Unmarshal(&out, env.WithTagDecoder("custom", fail))
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+	if got, want := out.Name, "default"; got != want {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_TagDecoderRequiredUnset_ReturnsRequirementError(t *testing.T) {
+	decode := func(string, any) error {
+		return nil
+	}
+	sealed := env.SealedEnvironment{}
+	var out struct {
+		Name string `env:"NAME,required,custom"`
+	}
+
+	err := sealed.Unmarshal(&out, env.WithTagDecoder("custom", decode))
+
+	if got, want := err, env.ErrRequirement; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_TagDecoderNotSupplied_ReturnsInvalidTagOptionError(t *testing.T) {
+	sealed := env.SealedEnvironment{"NAME": "value"}
+	var out struct {
+		Name string `env:"NAME,custom"`
+	}
+
+	err := sealed.Unmarshal(&out)
+
+	var tagErr *env.InvalidTagOptionError
+	if !errors.As(err, &tagErr) {
+		t.Fatalf("Unmarshal(): got err '%v', want InvalidTagOptionError", err)
+	}
+	if got, want := tagErr.Option, "custom"; got != want {
+		t.Errorf("Unmarshal(): got option '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshalReader(t *testing.T) {
+	input := strings.Join([]string{
+		"# database settings",
+		"export HOST=localhost",
+		"PORT=5432",
+		`NAMES="a,b"`,
+	}, "\n")
+	var got struct {
+		Host  string   `env:"HOST,required"`
+		Port  int      `env:"PORT"`
+		Names []string `env:"NAMES"`
+	}
+
+	if err := env.UnmarshalReader(strings.NewReader(input), &got); err != nil {
+		t.Fatalf("UnmarshalReader(): unexpected error: %v", err)
+	}
+
+	if got, want := got.Host, "localhost"; got != want {
+		t.Errorf("UnmarshalReader(): got '%v', want '%v'", got, want)
+	}
+	if got, want := got.Port, 5432; got != want {
+		t.Errorf("UnmarshalReader(): got '%v', want '%v'", got, want)
+	}
+	if got, want := got.Names, []string{"a", "b"}; !cmp.Equal(got, want) {
+		t.Errorf("UnmarshalReader(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshalReader_MalformedText_ReturnsSyntaxError(t *testing.T) {
+	input := "HOST=localhost\nPORT=\"5432"
+	var out struct {
+		Port int `env:"PORT"`
+	}
+
+	err := env.UnmarshalReader(strings.NewReader(input), &out)
+
+	var syntaxErr *env.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("UnmarshalReader(): got err '%v', want SyntaxError", err)
+	}
+	if got, want := syntaxErr.Line, 2; got != want {
+		t.Errorf("UnmarshalReader(): got line '%v', want '%v'", got, want)
+	}
+	if got, want := err, env.ErrParse; cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("UnmarshalReader(): got err '%v', want it to not be '%v'", got, want)
+	}
+}
+
+func TestUnmarshalReader_InvalidValue_ReturnsParseError(t *testing.T) {
+	input := "PORT=abc"
+	var out struct {
+		Port int `env:"PORT"`
+	}
+
+	err := env.UnmarshalReader(strings.NewReader(input), &out)
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("UnmarshalReader(): got err '%v', want '%v'", got, want)
+	}
+	if got, want := err, env.ErrSyntax; cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("UnmarshalReader(): got err '%v', want it to not be '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_ScalarSlices(t *testing.T) {
+	type ScalarSliceEnv struct {
+		Flags     []bool      `env:"FLAGS"`
+		FlagsSemi []bool      `env:"FLAGS_SEMI,sep=;"`
+		Floats    []float64   `env:"FLOATS"`
+		Times     []time.Time `env:"TIMES,sep= "`
+	}
+
+	testCases := []struct {
+		name string
+		env  env.SealedEnvironment
+		want ScalarSliceEnv
+	}{
+		{
+			name: "Bool slice",
+			env:  env.SealedEnvironment{"FLAGS": "true,false,1,0"},
+			want: ScalarSliceEnv{Flags: []bool{true, false, true, false}},
+		}, {
+			name: "Bool slice with custom separator",
+			env:  env.SealedEnvironment{"FLAGS_SEMI": "T;F;true"},
+			want: ScalarSliceEnv{FlagsSemi: []bool{true, false, true}},
+		}, {
+			name: "Float slice",
+			env:  env.SealedEnvironment{"FLOATS": "1.5,-2,3e2"},
+			want: ScalarSliceEnv{Floats: []float64{1.5, -2, 300}},
+		}, {
+			name: "Time slice",
+			env:  env.SealedEnvironment{"TIMES": "2024-01-02T03:04:05Z 2025-06-07T08:09:10Z"},
+			want: ScalarSliceEnv{Times: []time.Time{
+				time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				time.Date(2025, 6, 7, 8, 9, 10, 0, time.UTC),
+			}},
+		}, {
+			name: "Empty values",
+			env:  env.SealedEnvironment{"FLAGS": "", "FLOATS": "", "TIMES": ""},
+			want: ScalarSliceEnv{Flags: []bool{}, Floats: []float64{}, Times: []time.Time{}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got ScalarSliceEnv
+			if err := tc.env.Unmarshal(&got); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_ScalarSliceElementError_ReportsIndexAndValue(t *testing.T) {
+	var out struct {
+		Flags     []bool      `env:"FLAGS"`
+		FlagsSemi []bool      `env:"FLAGS_SEMI,sep=;"`
+		Floats    []float64   `env:"FLOATS"`
+		Times     []time.Time `env:"TIMES"`
+	}
+
+	testCases := []struct {
+		name      string
+		env       env.SealedEnvironment
+		wantIndex int
+		wantValue string
+	}{
+		{
+			name:      "Bool slice",
+			env:       env.SealedEnvironment{"FLAGS": "true,maybe"},
+			wantIndex: 1,
+			wantValue: "maybe",
+		}, {
+			name:      "Bool slice with custom separator",
+			env:       env.SealedEnvironment{"FLAGS_SEMI": "true;false;true,false"},
+			wantIndex: 2,
+			wantValue: "true,false",
+		}, {
+			name:      "Float slice",
+			env:       env.SealedEnvironment{"FLOATS": "abc,1.5"},
+			wantIndex: 0,
+			wantValue: "abc",
+		}, {
+			name:      "Time slice",
+			env:       env.SealedEnvironment{"TIMES": "2024-01-02T03:04:05Z,2024-13-01,yesterday"},
+			wantIndex: 1,
+			wantValue: "2024-13-01",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.env.Unmarshal(&out)
+
+			var indexErr *env.IndexError
+			if !errors.As(err, &indexErr) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want IndexError", tc.name, err)
+			}
+			if got, want := indexErr.Index, tc.wantIndex; got != want {
+				t.Errorf("Unmarshal(%s): got index '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := indexErr.Value, tc.wantValue; got != want {
+				t.Errorf("Unmarshal(%s): got value '%v', want '%v'", tc.name, got, want)
+			}
+			if got, want := err, env.ErrParse; !errors.Is(got, want) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Require(t *testing.T) {
+	type Server struct {
+		Name string `env:"NAME"`
+		Host string `env:"HOST"`
+	}
+	type RequireEnv struct {
+		Name    string   `env:"NAME"`
+		Port    int      `env:"PORT"`
+		Tagged  string   `env:"TAGGED,required"`
+		Servers []Server `env:"SERVERS"`
+	}
+
+	testCases := []struct {
+		name    string
+		env     env.SealedEnvironment
+		opts    []env.UnmarshalOption
+		wantKey string
+	}{
+		{
+			name:    "Without option",
+			env:     env.SealedEnvironment{"TAGGED": "x"},
+			wantKey: "",
+		}, {
+			name:    "Required key unset",
+			env:     env.SealedEnvironment{"TAGGED": "x"},
+			opts:    []env.UnmarshalOption{env.Require("PORT")},
+			wantKey: "PORT",
+		}, {
+			name:    "Required keys set",
+			env:     env.SealedEnvironment{"TAGGED": "x", "NAME": "app", "PORT": "80"},
+			opts:    []env.UnmarshalOption{env.Require("NAME", "PORT")},
+			wantKey: "",
+		}, {
+			name:    "Tag requirement still applies",
+			env:     env.SealedEnvironment{"NAME": "app"},
+			opts:    []env.UnmarshalOption{env.Require("NAME")},
+			wantKey: "TAGGED",
+		}, {
+			name:    "Prefixed key of struct slice element",
+			env:     env.SealedEnvironment{"TAGGED": "x", "SERVERS_0_NAME": "a"},
+			opts:    []env.UnmarshalOption{env.Require("SERVERS_0_HOST")},
+			wantKey: "SERVERS_0_HOST",
+		}, {
+			name:    "Unknown key is ignored",
+			env:     env.SealedEnvironment{"TAGGED": "x"},
+			opts:    []env.UnmarshalOption{env.Require("UNKNOWN")},
+			wantKey: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out RequireEnv
+			err := tc.env.Unmarshal(&out, tc.opts...)
+
+			var reqErr *env.RequirementError
+			if tc.wantKey == "" {
+				if err != nil {
+					t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+				}
+				return
+			}
+			if !errors.As(err, &reqErr) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want RequirementError", tc.name, err)
+			}
+			if got, want := reqErr.Key, tc.wantKey; got != want {
+				t.Errorf("Unmarshal(%s): got key '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_SQLNullTypes(t *testing.T) {
+	type SQLEnv struct {
+		Name    sql.NullString `env:"NAME"`
+		Port    sql.NullInt64  `env:"PORT"`
+		Enabled *sql.NullBool  `env:"ENABLED"`
+	}
+
+	testCases := []struct {
+		name string
+		env  env.SealedEnvironment
+		want SQLEnv
+	}{
+		{
+			name: "Set values are valid",
+			env:  env.SealedEnvironment{"NAME": "db", "PORT": "5432", "ENABLED": "true"},
+			want: SQLEnv{
+				Name:    sql.NullString{String: "db", Valid: true},
+				Port:    sql.NullInt64{Int64: 5432, Valid: true},
+				Enabled: &sql.NullBool{Bool: true, Valid: true},
+			},
+		}, {
+			name: "Empty string is valid",
+			env:  env.SealedEnvironment{"NAME": ""},
+			want: SQLEnv{Name: sql.NullString{Valid: true}},
+		}, {
+			name: "Unset values are not valid",
+			env:  env.SealedEnvironment{},
+			want: SQLEnv{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got SQLEnv
+			if err := tc.env.Unmarshal(&got); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_SQLNullTypeInvalid_ReturnsParseError(t *testing.T) {
+	sealed := env.SealedEnvironment{"PORT": "abc"}
+	var out struct {
+		Port sql.NullInt64 `env:"PORT"`
+	}
+
+	err := sealed.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+	if got, want := out.Port.Valid, false; got != want {
+		t.Errorf("Unmarshal(): got valid '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_DurationUnit(t *testing.T) {
+	type UnitEnv struct {
+		Timeout  time.Duration   `env:"TIMEOUT,unit=s"`
+		Interval time.Duration   `env:"INTERVAL,unit=ms"`
+		Backoff  []time.Duration `env:"BACKOFF,unit=us"`
+		Plain    time.Duration   `env:"PLAIN"`
+	}
+
+	testCases := []struct {
+		name string
+		env  env.SealedEnvironment
+		want UnitEnv
+	}{
+		{
+			name: "Bare integers use the unit",
+			env:  env.SealedEnvironment{"TIMEOUT": "5", "INTERVAL": "250"},
+			want: UnitEnv{Timeout: 5 * time.Second, Interval: 250 * time.Millisecond},
+		}, {
+			name: "Explicit suffix takes precedence",
+			env:  env.SealedEnvironment{"TIMEOUT": "2m", "INTERVAL": "5s"},
+			want: UnitEnv{Timeout: 2 * time.Minute, Interval: 5 * time.Second},
+		}, {
+			name: "Slice elements use the unit",
+			env:  env.SealedEnvironment{"BACKOFF": "100,1ms"},
+			want: UnitEnv{Backoff: []time.Duration{100 * time.Microsecond, time.Millisecond}},
+		}, {
+			name: "Zero without unit",
+			env:  env.SealedEnvironment{"PLAIN": "0"},
+			want: UnitEnv{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got UnitEnv
+			if err := tc.env.Unmarshal(&got); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_DurationWithoutUnit_ReturnsParseError(t *testing.T) {
+	sealed := env.SealedEnvironment{"TIMEOUT": "5"}
+	var out struct {
+		Timeout time.Duration `env:"TIMEOUT"`
+	}
+
+	err := sealed.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_InvalidUnit_ReturnsInvalidTagOptionError(t *testing.T) {
+	testCases := []struct {
+		name string
+		out  any
+	}{
+		{
+			name: "Unknown unit",
+			out: &struct {
+				Timeout time.Duration `env:"TIMEOUT,unit=d"`
+			}{},
+		}, {
+			name: "Non-duration field",
+			out: &struct {
+				Timeout int `env:"TIMEOUT,unit=s"`
+			}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := env.SealedEnvironment{"TIMEOUT": "5"}.Unmarshal(tc.out)
+
+			if got, want := err, env.ErrInvalidTagOption; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Errorf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_PointerScalars_TriState(t *testing.T) {
+	type PointerScalarEnv struct {
+		Bool     *bool          `env:"BOOL"`
+		Int      *int           `env:"INT"`
+		Float    *float64       `env:"FLOAT"`
+		Duration *time.Duration `env:"DURATION"`
+		Time     *time.Time     `env:"TIME"`
+	}
+	ptr := func(v any) any {
+		rv := reflect.New(reflect.TypeOf(v))
+		rv.Elem().Set(reflect.ValueOf(v))
+		return rv.Interface()
+	}
+
+	testCases := []struct {
+		name  string
+		env   env.SealedEnvironment
+		field string
+		want  any
+	}{
+		{name: "Bool set to true", env: env.SealedEnvironment{"BOOL": "true"}, field: "Bool", want: ptr(true)},
+		{name: "Bool set to false", env: env.SealedEnvironment{"BOOL": "false"}, field: "Bool", want: ptr(false)},
+		{name: "Bool unset", env: env.SealedEnvironment{}, field: "Bool", want: (*bool)(nil)},
+		{name: "Int set to zero", env: env.SealedEnvironment{"INT": "0"}, field: "Int", want: ptr(0)},
+		{name: "Int set", env: env.SealedEnvironment{"INT": "-42"}, field: "Int", want: ptr(-42)},
+		{name: "Int unset", env: env.SealedEnvironment{}, field: "Int", want: (*int)(nil)},
+		{name: "Float set to zero", env: env.SealedEnvironment{"FLOAT": "0"}, field: "Float", want: ptr(0.0)},
+		{name: "Float set", env: env.SealedEnvironment{"FLOAT": "2.5"}, field: "Float", want: ptr(2.5)},
+		{name: "Float unset", env: env.SealedEnvironment{}, field: "Float", want: (*float64)(nil)},
+		{name: "Duration set to zero", env: env.SealedEnvironment{"DURATION": "0s"}, field: "Duration", want: ptr(time.Duration(0))},
+		{name: "Duration set", env: env.SealedEnvironment{"DURATION": "1m30s"}, field: "Duration", want: ptr(90 * time.Second)},
+		{name: "Duration unset", env: env.SealedEnvironment{}, field: "Duration", want: (*time.Duration)(nil)},
+		{name: "Time set", env: env.SealedEnvironment{"TIME": "2024-01-02T03:04:05Z"}, field: "Time", want: ptr(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))},
+		{name: "Time unset", env: env.SealedEnvironment{}, field: "Time", want: (*time.Time)(nil)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out PointerScalarEnv
+			if err := tc.env.Unmarshal(&out); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			got := reflect.ValueOf(out).FieldByName(tc.field).Interface()
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_PointerScalarsInvalid_StayNil(t *testing.T) {
+	testCases := []struct {
+		name string
+		env  env.SealedEnvironment
+	}{
+		{name: "Bool", env: env.SealedEnvironment{"BOOL": "maybe"}},
+		{name: "Empty bool", env: env.SealedEnvironment{"BOOL": ""}},
+		{name: "Int", env: env.SealedEnvironment{"INT": "abc"}},
+		{name: "Float", env: env.SealedEnvironment{"FLOAT": "abc"}},
+		{name: "Duration", env: env.SealedEnvironment{"DURATION": "soon"}},
+		{name: "Time", env: env.SealedEnvironment{"TIME": "yesterday"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out struct {
+				Bool     *bool          `env:"BOOL"`
+				Int      *int           `env:"INT"`
+				Float    *float64       `env:"FLOAT"`
+				Duration *time.Duration `env:"DURATION"`
+				Time     *time.Time     `env:"TIME"`
+			}
+
+			err := tc.env.Unmarshal(&out)
+
+			if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Unmarshal(%s): got err '%v', want '%v'", tc.name, got, want)
+			}
+			rv := reflect.ValueOf(out)
+			for i := 0; i < rv.NumField(); i++ {
+				if field := rv.Field(i); !field.IsNil() {
+					t.Errorf("Unmarshal(%s): got '%v' for field %s, want nil", tc.name, field, rv.Type().Field(i).Name)
+				}
+			}
+		})
+	}
+}
+
+type unexportedCommon struct {
+	Name   string `env:"NAME"`
+	secret string
+}
+
+type unexportedOptional struct {
+	Zone string `env:"ZONE"`
+}
+
+func TestUnmarshal_UnexportedEmbeddedStruct(t *testing.T) {
+	type EmbeddedEnv struct {
+		unexportedCommon
+		Port int `env:"PORT"`
+	}
+	sealed := env.SealedEnvironment{"NAME": "example", "PORT": "8080"}
+
+	var got EmbeddedEnv
+	if err := sealed.Unmarshal(&got); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := EmbeddedEnv{unexportedCommon: unexportedCommon{Name: "example"}, Port: 8080}
+	if !cmp.Equal(got, want, cmp.AllowUnexported(EmbeddedEnv{}, unexportedCommon{})) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_UnexportedEmbeddedPointer(t *testing.T) {
+	type EmbeddedPointerEnv struct {
+		*unexportedOptional
+	}
+
+	testCases := []struct {
+		name    string
+		env     env.SealedEnvironment
+		out     EmbeddedPointerEnv
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Unset stays nil",
+			env:  env.SealedEnvironment{},
+		}, {
+			name: "Existing pointer is decoded",
+			env:  env.SealedEnvironment{"ZONE": "east"},
+			out:  EmbeddedPointerEnv{&unexportedOptional{}},
+			want: "east",
+		}, {
+			name:    "Nil pointer cannot be allocated",
+			env:     env.SealedEnvironment{"ZONE": "east"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := tc.out
+			err := tc.env.Unmarshal(&out)
+
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("Unmarshal(%s): got err '%v', want error %v", tc.name, err, want)
+			}
+			if out.unexportedOptional == nil {
+				if tc.want != "" {
+					t.Fatalf("Unmarshal(%s): got nil, want '%v'", tc.name, tc.want)
+				}
+				return
+			}
+			if got, want := out.Zone, tc.want; got != want {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+type SelfEmbeddingNode struct {
+	*SelfEmbeddingNode
+	X int `env:"X,required"`
+}
+
+type MutualEmbeddingA struct {
+	*MutualEmbeddingB
+	A int `env:"A"`
+}
+
+type MutualEmbeddingB struct {
+	*MutualEmbeddingA
+	B int `env:"B"`
+}
+
+func TestUnmarshal_SelfEmbeddingPointer(t *testing.T) {
+	sut := env.SealedEnvironment{"X": "1"}
+
+	var out SelfEmbeddingNode
+	if err := sut.Unmarshal(&out); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := out, (SelfEmbeddingNode{X: 1}); !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestUnmarshal_MutuallyEmbeddingPointers(t *testing.T) {
+	sut := env.SealedEnvironment{"A": "1", "B": "2"}
+
+	var out MutualEmbeddingA
+	if err := sut.Unmarshal(&out); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	want := MutualEmbeddingA{A: 1, MutualEmbeddingB: &MutualEmbeddingB{B: 2}}
+	if got := out; !cmp.Equal(got, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestSelfEmbeddingPointer_DoesNotRecurse(t *testing.T) {
+	in := SelfEmbeddingNode{X: 1}
+
+	if got, err := env.Keys(in); err != nil || !cmp.Equal(got, []string{"X"}) {
+		t.Errorf("Keys(): got '%v', err '%v', want '%v'", got, err, []string{"X"})
+	}
+	missing, err := env.MissingRequired(in, env.WithLookup(env.Environment{}.LookupEnv))
+	if err != nil || !cmp.Equal(missing, []string{"X"}) {
+		t.Errorf("MissingRequired(): got '%v', err '%v', want '%v'", missing, err, []string{"X"})
+	}
+	if _, err := env.Template(in); err != nil {
+		t.Errorf("Template(): unexpected error: %v", err)
+	}
+	if got, err := env.Marshal(in); err != nil || !cmp.Equal(got, env.Environment{"X": "1"}) {
+		t.Errorf("Marshal(): got '%v', err '%v', want '%v'", got, err, env.Environment{"X": "1"})
+	}
+	if _, err := env.Hash(in); err != nil {
+		t.Errorf("Hash(): unexpected error: %v", err)
+	}
+}
@@ -1,7 +1,13 @@
 package env
 
 import (
+	"encoding"
+	"io"
+	"math/big"
+	"os"
 	"reflect"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -18,15 +24,10 @@ func (v Value) Decode(value any, opts ...UnmarshalOption) error {
 	}
 
 	const key = "Value"
-	tag := &tagOptions{
-		key:   key,
-		value: string(v),
-		set:   true,
-		sep:   ",",
-	}
-	for _, opt := range opts {
-		opt.apply(tag)
-	}
+	tag := newTagOptions(opts...)
+	tag.key = key
+	tag.value = string(v)
+	tag.set = true
 	rv := reflect.ValueOf(value)
 	for rv.Kind() == reflect.Ptr {
 		if rv.IsNil() {
@@ -43,11 +44,70 @@ func (v Value) String() string {
 	return string(v)
 }
 
+// MarshalText returns the value verbatim, so that it is encoded as a plain
+// string by packages such as [encoding/json].
+func (v Value) MarshalText() ([]byte, error) {
+	return []byte(v), nil
+}
+
+// UnmarshalText sets the value to a copy of the text verbatim.
+func (v *Value) UnmarshalText(text []byte) error {
+	*v = Value(text)
+	return nil
+}
+
+var (
+	_ encoding.TextMarshaler   = Value("")
+	_ encoding.TextUnmarshaler = (*Value)(nil)
+)
+
+// Bytes returns the value as a byte slice.
+func (v Value) Bytes() []byte {
+	return []byte(v)
+}
+
+// Reader returns an [io.Reader] that reads from the value.
+func (v Value) Reader() io.Reader {
+	return strings.NewReader(string(v))
+}
+
+// Split slices the value into all sub-values separated by sep, mirroring the
+// behavior of the [Separator] option for slice values.
+//
+// Like [strings.Split], an empty value yields a single empty element rather
+// than an empty slice.
+func (v Value) Split(sep string) []Value {
+	parts := strings.Split(string(v), sep)
+	result := make([]Value, 0, len(parts))
+	for _, part := range parts {
+		result = append(result, Value(part))
+	}
+	return result
+}
+
+// Ints splits the value by sep and returns each part as an int, returning any
+// errors that may occur. Unlike [Value.Split], an empty value yields an empty
+// slice.
+// See [Unmarshal] for more details on the possible errors that may be returned.
+func (v Value) Ints(sep string) ([]int, error) {
+	var result []int
+	if err := v.Decode(&result, Separator(sep)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // Bool returns the value as a bool and returns any errors that may occur.
 // See [Unmarshal] for more details on the possible errors that may be returned.
 func (v Value) Bool() (bool, error) {
+	return v.BoolWith()
+}
+
+// BoolWith is like [Value.Bool], but accepts options such as [ExtendedBool] or
+// [BoolParser] to control how the value is parsed.
+func (v Value) BoolWith(opts ...UnmarshalOption) (bool, error) {
 	var result bool
-	err := v.Decode(&result)
+	err := v.Decode(&result, opts...)
 	return result, err
 }
 
@@ -59,6 +119,16 @@ func (v Value) Int() (int, error) {
 	return result, err
 }
 
+// IntBase returns the value as an int parsed in the given base, rather than
+// inferring the base from the prefix of the value, and returns any errors that
+// may occur.
+// See [Unmarshal] for more details on the possible errors that may be returned.
+func (v Value) IntBase(base int) (int, error) {
+	var result int
+	err := v.Decode(&result, Base(base))
+	return result, err
+}
+
 // Int8 returns the value as an int8 and returns any errors that may occur.
 // See [Unmarshal] for more details on the possible errors that may be returned.
 func (v Value) Int8() (int8, error) {
@@ -91,6 +161,30 @@ func (v Value) Int64() (int64, error) {
 	return result, err
 }
 
+// Bytes64 returns the value as a human-readable size in bytes, such as "10MB"
+// or "1.5GiB", and returns any errors that may occur. See the `bytes` option in
+// the package documentation for the exact units that are accepted.
+// See [Unmarshal] for more details on the possible errors that may be returned.
+func (v Value) Bytes64() (int64, error) {
+	var result int64
+	err := v.Decode(&result, apply(func(tag *tagOptions) {
+		tag.byteSize = true
+	}))
+	return result, err
+}
+
+// Percent returns the value as a percentage, such as "75%", in fractions of
+// one, and returns any errors that may occur. See the `percent` option in the
+// package documentation for the exact format that is accepted.
+// See [Unmarshal] for more details on the possible errors that may be returned.
+func (v Value) Percent() (float64, error) {
+	var result float64
+	err := v.Decode(&result, apply(func(tag *tagOptions) {
+		tag.percent = true
+	}))
+	return result, err
+}
+
 // Uint returns the value as an uint and returns any errors that may occur.
 // See [Unmarshal] for more details on the possible errors that may be returned.
 func (v Value) Uint() (uint, error) {
@@ -147,6 +241,57 @@ func (v Value) Float64() (float64, error) {
 	return result, err
 }
 
+// Complex64 returns the value as a complex64 and returns any errors that may
+// occur.
+// See [Unmarshal] for more details on the possible errors that may be returned.
+func (v Value) Complex64() (complex64, error) {
+	var result complex64
+	err := v.Decode(&result)
+	return result, err
+}
+
+// Complex128 returns the value as a complex128 and returns any errors that may
+// occur.
+// See [Unmarshal] for more details on the possible errors that may be returned.
+func (v Value) Complex128() (complex128, error) {
+	var result complex128
+	err := v.Decode(&result)
+	return result, err
+}
+
+// BigInt returns the value as a [big.Int] and returns any errors that may
+// occur. The base is detected from the 0x, 0o, or 0b prefix, if present.
+// See [Unmarshal] for more details on the possible errors that may be returned.
+func (v Value) BigInt() (*big.Int, error) {
+	var result big.Int
+	if err := v.Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// BigFloat returns the value as a [big.Float] and returns any errors that may
+// occur.
+// See [Unmarshal] for more details on the possible errors that may be returned.
+func (v Value) BigFloat() (*big.Float, error) {
+	var result big.Float
+	if err := v.Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Regexp compiles the value as a [regexp.Regexp] and returns any errors that
+// may occur.
+// See [Unmarshal] for more details on the possible errors that may be returned.
+func (v Value) Regexp() (*regexp.Regexp, error) {
+	var result *regexp.Regexp
+	if err := v.Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // Duration returns the value as a [time.Duration] and returns any errors that
 // may occur.
 // See [Unmarshal] for more details on the possible errors that may be returned.
@@ -156,6 +301,25 @@ func (v Value) Duration() (time.Duration, error) {
 	return result, err
 }
 
+// DurationSeconds returns the value as a [time.Duration], interpreting a bare
+// integer as a number of seconds, and returns any errors that may occur.
+// Values that include a unit, such as "500ms", are parsed as-is.
+// See [Unmarshal] for more details on the possible errors that may be returned.
+func (v Value) DurationSeconds() (time.Duration, error) {
+	var result time.Duration
+	err := v.Decode(&result, DurationUnit(time.Second))
+	return result, err
+}
+
+// FileMode returns the value as an [os.FileMode] parsed from octal permission
+// bits, such as "0644", and returns any errors that may occur.
+// See [Unmarshal] for more details on the possible errors that may be returned.
+func (v Value) FileMode() (os.FileMode, error) {
+	var result os.FileMode
+	err := v.Decode(&result)
+	return result, err
+}
+
 // Time returns the value as a [time.Time] and returns any errors that may occur.
 // See [Unmarshal] for more details on the possible errors that may be returned.
 func (v Value) Time() (time.Time, error) {
@@ -163,3 +327,174 @@ func (v Value) Time() (time.Time, error) {
 	err := v.Decode(&result)
 	return result, err
 }
+
+// must returns the result, panicking with the error if it is non-nil.
+func must[T any](result T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// MustInts is like [Value.Ints], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Ints].
+func (v Value) MustInts(sep string) []int {
+	return must(v.Ints(sep))
+}
+
+// MustBool is like [Value.Bool], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Bool].
+func (v Value) MustBool() bool {
+	return must(v.Bool())
+}
+
+// MustBoolWith is like [Value.BoolWith], but panics if the value cannot be
+// parsed. The panic value is the error returned from [Value.BoolWith].
+func (v Value) MustBoolWith(opts ...UnmarshalOption) bool {
+	return must(v.BoolWith(opts...))
+}
+
+// MustInt is like [Value.Int], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Int].
+func (v Value) MustInt() int {
+	return must(v.Int())
+}
+
+// MustIntBase is like [Value.IntBase], but panics if the value cannot be
+// parsed. The panic value is the error returned from [Value.IntBase].
+func (v Value) MustIntBase(base int) int {
+	return must(v.IntBase(base))
+}
+
+// MustInt8 is like [Value.Int8], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Int8].
+func (v Value) MustInt8() int8 {
+	return must(v.Int8())
+}
+
+// MustInt16 is like [Value.Int16], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Int16].
+func (v Value) MustInt16() int16 {
+	return must(v.Int16())
+}
+
+// MustInt32 is like [Value.Int32], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Int32].
+func (v Value) MustInt32() int32 {
+	return must(v.Int32())
+}
+
+// MustInt64 is like [Value.Int64], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Int64].
+func (v Value) MustInt64() int64 {
+	return must(v.Int64())
+}
+
+// MustBytes64 is like [Value.Bytes64], but panics if the value cannot be
+// parsed. The panic value is the error returned from [Value.Bytes64].
+func (v Value) MustBytes64() int64 {
+	return must(v.Bytes64())
+}
+
+// MustPercent is like [Value.Percent], but panics if the value cannot be
+// parsed. The panic value is the error returned from [Value.Percent].
+func (v Value) MustPercent() float64 {
+	return must(v.Percent())
+}
+
+// MustUint is like [Value.Uint], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Uint].
+func (v Value) MustUint() uint {
+	return must(v.Uint())
+}
+
+// MustUint8 is like [Value.Uint8], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Uint8].
+func (v Value) MustUint8() uint8 {
+	return must(v.Uint8())
+}
+
+// MustUint16 is like [Value.Uint16], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Uint16].
+func (v Value) MustUint16() uint16 {
+	return must(v.Uint16())
+}
+
+// MustUint32 is like [Value.Uint32], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Uint32].
+func (v Value) MustUint32() uint32 {
+	return must(v.Uint32())
+}
+
+// MustUint64 is like [Value.Uint64], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Uint64].
+func (v Value) MustUint64() uint64 {
+	return must(v.Uint64())
+}
+
+// MustFloat32 is like [Value.Float32], but panics if the value cannot be
+// parsed. The panic value is the error returned from [Value.Float32].
+func (v Value) MustFloat32() float32 {
+	return must(v.Float32())
+}
+
+// MustFloat64 is like [Value.Float64], but panics if the value cannot be
+// parsed. The panic value is the error returned from [Value.Float64].
+func (v Value) MustFloat64() float64 {
+	return must(v.Float64())
+}
+
+// MustComplex64 is like [Value.Complex64], but panics if the value cannot be
+// parsed. The panic value is the error returned from [Value.Complex64].
+func (v Value) MustComplex64() complex64 {
+	return must(v.Complex64())
+}
+
+// MustComplex128 is like [Value.Complex128], but panics if the value cannot be
+// parsed. The panic value is the error returned from [Value.Complex128].
+func (v Value) MustComplex128() complex128 {
+	return must(v.Complex128())
+}
+
+// MustBigInt is like [Value.BigInt], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.BigInt].
+func (v Value) MustBigInt() *big.Int {
+	return must(v.BigInt())
+}
+
+// MustBigFloat is like [Value.BigFloat], but panics if the value cannot be
+// parsed. The panic value is the error returned from [Value.BigFloat].
+func (v Value) MustBigFloat() *big.Float {
+	return must(v.BigFloat())
+}
+
+// MustRegexp is like [Value.Regexp], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Regexp].
+func (v Value) MustRegexp() *regexp.Regexp {
+	return must(v.Regexp())
+}
+
+// MustDuration is like [Value.Duration], but panics if the value cannot be
+// parsed. The panic value is the error returned from [Value.Duration].
+func (v Value) MustDuration() time.Duration {
+	return must(v.Duration())
+}
+
+// MustDurationSeconds is like [Value.DurationSeconds], but panics if the value
+// cannot be parsed. The panic value is the error returned from
+// [Value.DurationSeconds].
+func (v Value) MustDurationSeconds() time.Duration {
+	return must(v.DurationSeconds())
+}
+
+// MustFileMode is like [Value.FileMode], but panics if the value cannot be
+// parsed. The panic value is the error returned from [Value.FileMode].
+func (v Value) MustFileMode() os.FileMode {
+	return must(v.FileMode())
+}
+
+// MustTime is like [Value.Time], but panics if the value cannot be parsed.
+// The panic value is the error returned from [Value.Time].
+func (v Value) MustTime() time.Time {
+	return must(v.Time())
+}
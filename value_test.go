@@ -1,6 +1,13 @@
 package env_test
 
 import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
 	"testing"
 	"time"
 
@@ -45,6 +52,95 @@ func TestValueBool(t *testing.T) {
 	}
 }
 
+func TestValueBoolWith_ExtendedBool(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		want    bool
+		wantErr error
+	}{
+		{
+			name:  "Yes value",
+			value: env.Value("YES"),
+			want:  true,
+		}, {
+			name:  "Off value",
+			value: env.Value("Off"),
+			want:  false,
+		}, {
+			name:  "Strict value",
+			value: env.Value("1"),
+			want:  true,
+		}, {
+			name:    "Unknown value",
+			value:   env.Value("maybe"),
+			want:    false,
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.BoolWith(env.ExtendedBool())
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.BoolWith(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Value.BoolWith(%s): got '%v', want '%v'", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValueBoolWith_BoolParser(t *testing.T) {
+	parse := func(s string) (bool, error) {
+		switch s {
+		case "ja":
+			return true, nil
+		case "nein":
+			return false, nil
+		}
+		return false, fmt.Errorf("unknown bool %q", s)
+	}
+	testCases := []struct {
+		name    string
+		value   env.Value
+		want    bool
+		wantErr error
+	}{
+		{
+			name:  "Truthy value",
+			value: env.Value("ja"),
+			want:  true,
+		}, {
+			name:  "Falsy value",
+			value: env.Value("nein"),
+			want:  false,
+		}, {
+			name:    "Default value no longer accepted",
+			value:   env.Value("true"),
+			want:    false,
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.BoolWith(env.BoolParser(parse))
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.BoolWith(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Value.BoolWith(%s): got '%v', want '%v'", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
 func TestValueInt(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -81,6 +177,57 @@ func TestValueInt(t *testing.T) {
 	}
 }
 
+func TestValueIntBase(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		base    int
+		want    int
+		wantErr error
+	}{
+		{
+			name:  "Bare hexadecimal value",
+			value: env.Value("ff"),
+			base:  16,
+			want:  255,
+		}, {
+			name:  "Binary value",
+			value: env.Value("101"),
+			base:  2,
+			want:  5,
+		}, {
+			name:  "Leading zero in base 10",
+			value: env.Value("010"),
+			base:  10,
+			want:  10,
+		}, {
+			name:    "Prefixed value with fixed base",
+			value:   env.Value("0xff"),
+			base:    16,
+			wantErr: env.ErrParse,
+		}, {
+			name:    "Invalid base",
+			value:   env.Value("42"),
+			base:    1,
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.IntBase(tc.base)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.IntBase(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Value.IntBase(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
 func TestValueInt8(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -477,6 +624,217 @@ func TestValueFloat64(t *testing.T) {
 	}
 }
 
+func TestValueComplex64(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		want    complex64
+		wantErr error
+	}{
+		{
+			name:    "Valid complex64 value",
+			value:   env.Value("3+4i"),
+			want:    complex(3, 4),
+			wantErr: nil,
+		},
+		{
+			name:    "Valid real-only complex64 value",
+			value:   env.Value("1.5"),
+			want:    complex(1.5, 0),
+			wantErr: nil,
+		},
+		{
+			name:    "Invalid complex64 value",
+			value:   env.Value("not_a_complex64"),
+			want:    0,
+			wantErr: cmpopts.AnyError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.Complex64()
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.Complex64(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Value.Complex64(%s): got '%v', want '%v'", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValueComplex128(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		want    complex128
+		wantErr error
+	}{
+		{
+			name:    "Valid complex128 value",
+			value:   env.Value("3+4i"),
+			want:    complex(3, 4),
+			wantErr: nil,
+		},
+		{
+			name:    "Valid real-only complex128 value",
+			value:   env.Value("1.5"),
+			want:    complex(1.5, 0),
+			wantErr: nil,
+		},
+		{
+			name:    "Invalid complex128 value",
+			value:   env.Value("not_a_complex128"),
+			want:    0,
+			wantErr: cmpopts.AnyError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.Complex128()
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.Complex128(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Value.Complex128(%s): got '%v', want '%v'", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValueBigInt(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		want    *big.Int
+		wantErr error
+	}{
+		{
+			name:    "Valid decimal big.Int value",
+			value:   env.Value("123456789012345678901234567890"),
+			want:    func() *big.Int { i, _ := new(big.Int).SetString("123456789012345678901234567890", 10); return i }(),
+			wantErr: nil,
+		},
+		{
+			name:    "Valid hex big.Int value",
+			value:   env.Value("0xffffffffffffffffffff"),
+			want:    func() *big.Int { i, _ := new(big.Int).SetString("ffffffffffffffffffff", 16); return i }(),
+			wantErr: nil,
+		},
+		{
+			name:    "Valid binary big.Int value",
+			value:   env.Value("0b101"),
+			want:    big.NewInt(5),
+			wantErr: nil,
+		},
+		{
+			name:    "Invalid big.Int value",
+			value:   env.Value("not_a_big_int"),
+			want:    nil,
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.BigInt()
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.BigInt(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; (got == nil) != (want == nil) || (got != nil && got.Cmp(want) != 0) {
+				t.Errorf("Value.BigInt(%s): got '%v', want '%v'", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValueBigFloat(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		want    *big.Float
+		wantErr error
+	}{
+		{
+			name:    "Valid big.Float value",
+			value:   env.Value("3.25"),
+			want:    big.NewFloat(3.25),
+			wantErr: nil,
+		},
+		{
+			name:    "Valid hex big.Float value",
+			value:   env.Value("0x10"),
+			want:    big.NewFloat(16),
+			wantErr: nil,
+		},
+		{
+			name:    "Invalid big.Float value",
+			value:   env.Value("not_a_big_float"),
+			want:    nil,
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.BigFloat()
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.BigFloat(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; (got == nil) != (want == nil) || (got != nil && got.Cmp(want) != 0) {
+				t.Errorf("Value.BigFloat(%s): got '%v', want '%v'", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValueRegexp(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "Valid pattern",
+			value: env.Value("^/api/v[0-9]+/"),
+			want:  "^/api/v[0-9]+/",
+		},
+		{
+			name:    "Invalid pattern",
+			value:   env.Value("[unterminated"),
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.Regexp()
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.Regexp(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+			if err != nil {
+				return
+			}
+
+			if got, want := got.String(), tc.want; got != want {
+				t.Errorf("Value.Regexp(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
 func TestValueDuration(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -513,6 +871,108 @@ func TestValueDuration(t *testing.T) {
 	}
 }
 
+func TestValueDurationSeconds(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		want    time.Duration
+		wantErr error
+	}{
+		{
+			name:  "Bare integer",
+			value: env.Value("5"),
+			want:  5 * time.Second,
+		}, {
+			name:  "Negative integer",
+			value: env.Value("-2"),
+			want:  -2 * time.Second,
+		}, {
+			name:  "Explicit unit",
+			value: env.Value("500ms"),
+			want:  500 * time.Millisecond,
+		}, {
+			name:    "Fractional without unit",
+			value:   env.Value("1.5"),
+			wantErr: env.ErrParse,
+		}, {
+			name:    "Out of range",
+			value:   env.Value("9223372036854775807"),
+			wantErr: env.ErrParse,
+		}, {
+			name:    "Invalid value",
+			value:   env.Value("soon"),
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.DurationSeconds()
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.DurationSeconds(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Value.DurationSeconds(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestValueFileMode(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		want    os.FileMode
+		wantErr error
+	}{
+		{
+			name:  "Leading zero",
+			value: env.Value("0644"),
+			want:  0o644,
+		}, {
+			name:  "No leading zero",
+			value: env.Value("755"),
+			want:  0o755,
+		}, {
+			name:  "Octal prefix",
+			value: env.Value("0o022"),
+			want:  0o022,
+		}, {
+			name:    "Non-octal digits",
+			value:   env.Value("0648"),
+			wantErr: env.ErrParse,
+		}, {
+			name:    "Hexadecimal",
+			value:   env.Value("0x1ff"),
+			wantErr: env.ErrParse,
+		}, {
+			name:    "Out of range",
+			value:   env.Value("04755"),
+			wantErr: env.ErrParse,
+		}, {
+			name:    "Empty",
+			value:   env.Value(""),
+			wantErr: env.ErrParse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.FileMode()
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.FileMode(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; got != want {
+				t.Errorf("Value.FileMode(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
 func TestValueTime(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -526,6 +986,24 @@ func TestValueTime(t *testing.T) {
 			want:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
 			wantErr: nil,
 		},
+		{
+			name:    "RFC3339Nano time value",
+			value:   env.Value("2021-01-01T00:00:00.123456789Z"),
+			want:    time.Date(2021, 1, 1, 0, 0, 0, 123456789, time.UTC),
+			wantErr: nil,
+		},
+		{
+			name:    "DateOnly time value",
+			value:   env.Value("2021-01-01"),
+			want:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantErr: nil,
+		},
+		{
+			name:    "Kitchen time value",
+			value:   env.Value("3:04PM"),
+			want:    time.Date(0, 1, 1, 15, 4, 0, 0, time.UTC),
+			wantErr: nil,
+		},
 		{
 			name:    "Invalid time value",
 			value:   env.Value("not_a_time"),
@@ -573,6 +1051,161 @@ func TestValueString(t *testing.T) {
 	}
 }
 
+func TestValueBytes(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value env.Value
+		want  []byte
+	}{
+		{
+			name:  "Valid value",
+			value: env.Value("hello"),
+			want:  []byte("hello"),
+		}, {
+			name:  "Empty value",
+			value: env.Value(""),
+			want:  []byte{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.value.Bytes()
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Value.Bytes(%s): got '%v', want '%v'", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValueReader(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value env.Value
+		want  string
+	}{
+		{
+			name:  "Valid value",
+			value: env.Value("hello"),
+			want:  "hello",
+		}, {
+			name:  "Empty value",
+			value: env.Value(""),
+			want:  "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := io.ReadAll(tc.value.Reader())
+			if err != nil {
+				t.Fatalf("Value.Reader(%s): got error '%v', want error nil", tc.name, err)
+			}
+
+			if got, want := string(got), tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Value.Reader(%s): got '%v', want '%v'", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValueSplit(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value env.Value
+		sep   string
+		want  []env.Value
+	}{
+		{
+			name:  "Multiple values",
+			value: env.Value("a,b,c"),
+			sep:   ",",
+			want:  []env.Value{"a", "b", "c"},
+		}, {
+			name:  "Custom separator",
+			value: env.Value("a:b"),
+			sep:   ":",
+			want:  []env.Value{"a", "b"},
+		}, {
+			name:  "Empty value",
+			value: env.Value(""),
+			sep:   ",",
+			want:  []env.Value{""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.value.Split(tc.sep)
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Value.Split(%s): got '%v', want '%v'", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValueInts(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   env.Value
+		sep     string
+		want    []int
+		wantErr error
+	}{
+		{
+			name:  "Valid int values",
+			value: env.Value("1,2,3"),
+			sep:   ",",
+			want:  []int{1, 2, 3},
+		}, {
+			name:  "Custom separator",
+			value: env.Value("4;5"),
+			sep:   ";",
+			want:  []int{4, 5},
+		}, {
+			name:    "Invalid int value",
+			value:   env.Value("1,two"),
+			sep:     ",",
+			wantErr: env.ErrParse,
+		}, {
+			name:  "Empty value",
+			value: env.Value(""),
+			sep:   ",",
+			want:  []int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.value.Ints(tc.sep)
+
+			if got, want := err, tc.wantErr; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+				t.Fatalf("Value.Ints(%s): got error '%v', want error '%v'", tc.name, got, want)
+			}
+
+			if got, want := got, tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Value.Ints(%s): got '%v', want '%v'", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValueDecode_KeyValueSeparator(t *testing.T) {
+	value := env.Value("a:1;b:2")
+
+	var got map[string]int
+	err := value.Decode(&got, env.Separator(";"), env.KeyValueSeparator(":"))
+	if err != nil {
+		t.Fatalf("Value.Decode(): got error '%v', want error nil", err)
+	}
+
+	if got, want := got, map[string]int{"a": 1, "b": 2}; !cmp.Equal(got, want) {
+		t.Errorf("Value.Decode(): got '%v', want '%v'", got, want)
+	}
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -605,3 +1238,133 @@ func TestValuePointer(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkValueTime_RFC3339(b *testing.B) {
+	value := env.Value("2021-01-01T12:30:00Z")
+	for i := 0; i < b.N; i++ {
+		if _, err := value.Time(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValueTime_Kitchen(b *testing.B) {
+	value := env.Value("3:04PM")
+	for i := 0; i < b.N; i++ {
+		if _, err := value.Time(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestValue_Decode_CSVOption_HonorsQuotes(t *testing.T) {
+	var got []string
+	err := env.Value(`a,"b,c"`).Decode(&got, env.CSV())
+	if err != nil {
+		t.Fatalf("Value.Decode(): unexpected error: %v", err)
+	}
+
+	if want := []string{"a", "b,c"}; !cmp.Equal(got, want) {
+		t.Errorf("Value.Decode(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestValue_TextMarshaling_JSONRoundTrip(t *testing.T) {
+	type Wrapper struct {
+		Value  env.Value            `json:"value"`
+		Values map[env.Value]string `json:"values"`
+	}
+	want := Wrapper{
+		Value:  env.Value("Hello, \"World\""),
+		Values: map[env.Value]string{"key": "value"},
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal(): unexpected error: %v", err)
+	}
+	if got, want := string(data), `{"value":"Hello, \"World\"","values":{"key":"value"}}`; got != want {
+		t.Errorf("json.Marshal(): got '%v', want '%v'", got, want)
+	}
+
+	var got Wrapper
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal(): unexpected error: %v", err)
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("json.Unmarshal(): got '%v', want '%v'", got, want)
+	}
+}
+
+func TestValue_ImplementsTextInterfaces(t *testing.T) {
+	var value env.Value = "example"
+	var _ encoding.TextMarshaler = value
+	var _ encoding.TextUnmarshaler = &value
+
+	text, err := value.MarshalText()
+	if err != nil {
+		t.Fatalf("Value.MarshalText(): unexpected error: %v", err)
+	}
+	var got env.Value
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatalf("Value.UnmarshalText(): unexpected error: %v", err)
+	}
+
+	if got != value {
+		t.Errorf("Value.UnmarshalText(): got '%v', want '%v'", got, value)
+	}
+}
+
+func TestValueMust(t *testing.T) {
+	testCases := []struct {
+		name    string
+		valid   env.Value
+		want    any
+		invalid env.Value
+		must    func(env.Value) any
+	}{
+		{name: "MustInts", valid: "1,2", want: []int{1, 2}, invalid: "1,x", must: func(v env.Value) any { return v.MustInts(",") }},
+		{name: "MustBool", valid: "true", want: true, invalid: "maybe", must: func(v env.Value) any { return v.MustBool() }},
+		{name: "MustInt", valid: "42", want: 42, invalid: "x", must: func(v env.Value) any { return v.MustInt() }},
+		{name: "MustIntBase", valid: "ff", want: 255, invalid: "zz", must: func(v env.Value) any { return v.MustIntBase(16) }},
+		{name: "MustInt8", valid: "-8", want: int8(-8), invalid: "128", must: func(v env.Value) any { return v.MustInt8() }},
+		{name: "MustInt16", valid: "16", want: int16(16), invalid: "x", must: func(v env.Value) any { return v.MustInt16() }},
+		{name: "MustInt32", valid: "32", want: int32(32), invalid: "x", must: func(v env.Value) any { return v.MustInt32() }},
+		{name: "MustInt64", valid: "64", want: int64(64), invalid: "x", must: func(v env.Value) any { return v.MustInt64() }},
+		{name: "MustBytes64", valid: "1KiB", want: int64(1024), invalid: "1XB", must: func(v env.Value) any { return v.MustBytes64() }},
+		{name: "MustUint", valid: "1", want: uint(1), invalid: "-1", must: func(v env.Value) any { return v.MustUint() }},
+		{name: "MustUint8", valid: "8", want: uint8(8), invalid: "256", must: func(v env.Value) any { return v.MustUint8() }},
+		{name: "MustUint16", valid: "16", want: uint16(16), invalid: "x", must: func(v env.Value) any { return v.MustUint16() }},
+		{name: "MustUint32", valid: "32", want: uint32(32), invalid: "x", must: func(v env.Value) any { return v.MustUint32() }},
+		{name: "MustUint64", valid: "64", want: uint64(64), invalid: "x", must: func(v env.Value) any { return v.MustUint64() }},
+		{name: "MustFloat32", valid: "1.5", want: float32(1.5), invalid: "x", must: func(v env.Value) any { return v.MustFloat32() }},
+		{name: "MustFloat64", valid: "2.5", want: 2.5, invalid: "x", must: func(v env.Value) any { return v.MustFloat64() }},
+		{name: "MustComplex64", valid: "1+2i", want: complex64(1 + 2i), invalid: "x", must: func(v env.Value) any { return v.MustComplex64() }},
+		{name: "MustComplex128", valid: "3+4i", want: 3 + 4i, invalid: "x", must: func(v env.Value) any { return v.MustComplex128() }},
+		{name: "MustBigInt", valid: "10", want: "10", invalid: "x", must: func(v env.Value) any { return v.MustBigInt().String() }},
+		{name: "MustBigFloat", valid: "1.5", want: "1.5", invalid: "x", must: func(v env.Value) any { return v.MustBigFloat().String() }},
+		{name: "MustRegexp", valid: "^a+$", want: "^a+$", invalid: "(", must: func(v env.Value) any { return v.MustRegexp().String() }},
+		{name: "MustDuration", valid: "1s", want: time.Second, invalid: "x", must: func(v env.Value) any { return v.MustDuration() }},
+		{name: "MustDurationSeconds", valid: "30", want: 30 * time.Second, invalid: "x", must: func(v env.Value) any { return v.MustDurationSeconds() }},
+		{name: "MustFileMode", valid: "0644", want: os.FileMode(0o644), invalid: "9", must: func(v env.Value) any { return v.MustFileMode() }},
+		{name: "MustTime", valid: "2024-01-02", want: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), invalid: "x", must: func(v env.Value) any { return v.MustTime() }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, want := tc.must(tc.valid), tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Value.%s(%s): got '%v', want '%v'", tc.name, tc.valid, got, want)
+			}
+
+			defer func() {
+				err, _ := recover().(error)
+
+				var parseErr *env.ParseError
+				if !errors.As(err, &parseErr) {
+					t.Errorf("Value.%s(%s): got panic '%v', want ParseError", tc.name, tc.invalid, err)
+				}
+			}()
+			tc.must(tc.invalid)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+module rodusek.dev/pkg/env/yamlenv
+
+go 1.18
+
+require (
+	github.com/google/go-cmp v0.6.0
+	gopkg.in/yaml.v3 v3.0.1
+	rodusek.dev/pkg/env v0.0.0-00010101000000-000000000000
+)
+
+// The yamlenv module is developed alongside the env module it extends.
+replace rodusek.dev/pkg/env => ../
@@ -0,0 +1,46 @@
+/*
+Package yamlenv extends the env package with support for values written as
+inline YAML, which some platforms use to inject lists and maps into a single
+environment variable.
+
+Fields opt in to YAML decoding with the `yaml` tag option:
+
+	type Config struct {
+		Hosts  []string          `env:"HOSTS,yaml"`
+		Labels map[string]string `env:"LABELS,yaml"`
+	}
+
+With HOSTS set to "[a, b]" and LABELS set to "{team: core, tier: 1}", Hosts is
+decoded as ["a", "b"], and Labels as {"team": "core", "tier": "1"}.
+
+This is a separate module so that the env module itself does not depend on a
+YAML library.
+*/
+package yamlenv
+
+import (
+	"gopkg.in/yaml.v3"
+	"rodusek.dev/pkg/env"
+)
+
+// TagOption is the name of the tag option that decodes a field as YAML.
+const TagOption = "yaml"
+
+// Unmarshal is like [env.Unmarshal], but decodes the values of fields tagged
+// with the `yaml` option as YAML, using the rules of [yaml.Unmarshal]. All
+// other fields are decoded as usual. Malformed YAML is reported as an
+// [env.ParseError].
+func Unmarshal(out any, opts ...env.UnmarshalOption) error {
+	return env.Unmarshal(out, append(opts[:len(opts):len(opts)], Option())...)
+}
+
+// Option returns an [env.UnmarshalOption] that decodes the values of fields
+// tagged with the `yaml` option as YAML. This allows YAML values to be used
+// with the other ways of unmarshaling, such as [env.Environment.Unmarshal].
+func Option() env.UnmarshalOption {
+	return env.WithTagDecoder(TagOption, decode)
+}
+
+func decode(value string, out any) error {
+	return yaml.Unmarshal([]byte(value), out)
+}
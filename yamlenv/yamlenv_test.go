@@ -0,0 +1,107 @@
+package yamlenv_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"rodusek.dev/pkg/env"
+	"rodusek.dev/pkg/env/yamlenv"
+)
+
+type Server struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+type Config struct {
+	Hosts   []string          `env:"HOSTS,yaml"`
+	Labels  map[string]string `env:"LABELS,yaml"`
+	Servers []Server          `env:"SERVERS,yaml"`
+	Name    string            `env:"NAME"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	testCases := []struct {
+		name string
+		env  map[string]string
+		want Config
+	}{
+		{
+			name: "Flow sequence",
+			env:  map[string]string{"HOSTS": "[a, b, c]"},
+			want: Config{Hosts: []string{"a", "b", "c"}},
+		}, {
+			name: "Flow mapping",
+			env:  map[string]string{"LABELS": "{team: core, tier: 1}"},
+			want: Config{Labels: map[string]string{"team": "core", "tier": "1"}},
+		}, {
+			name: "Block sequence of mappings",
+			env:  map[string]string{"SERVERS": "- host: a\n  port: 80\n- host: b\n  port: 443\n"},
+			want: Config{Servers: []Server{{Host: "a", Port: 80}, {Host: "b", Port: 443}}},
+		}, {
+			name: "Other fields decode as usual",
+			env:  map[string]string{"NAME": "[not yaml]"},
+			want: Config{Name: "[not yaml]"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			var got Config
+			if err := yamlenv.Unmarshal(&got); err != nil {
+				t.Fatalf("Unmarshal(%s): unexpected error: %v", tc.name, err)
+			}
+
+			if want := tc.want; !cmp.Equal(got, want) {
+				t.Errorf("Unmarshal(%s): got '%v', want '%v'", tc.name, got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_MalformedYAML_ReturnsParseError(t *testing.T) {
+	t.Setenv("HOSTS", "[a, b")
+
+	var out Config
+	err := yamlenv.Unmarshal(&out)
+
+	if got, want := err, env.ErrParse; !cmp.Equal(got, want, cmpopts.EquateErrors()) {
+		t.Errorf("Unmarshal(): got err '%v', want '%v'", got, want)
+	}
+}
+
+func TestOption(t *testing.T) {
+	sealed := env.SealedEnvironment{"HOSTS": "[a, b]"}
+
+	var got Config
+	if err := sealed.Unmarshal(&got, yamlenv.Option()); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if want := []string{"a", "b"}; !cmp.Equal(got.Hosts, want) {
+		t.Errorf("Unmarshal(): got '%v', want '%v'", got.Hosts, want)
+	}
+}
+
+func TestUnmarshal_DoesNotModifyOptions(t *testing.T) {
+	t.Setenv("HOSTS", "[a]")
+	sentinel := env.Separator(";")
+	opts := make([]env.UnmarshalOption, 1, 2)
+	opts[0] = env.Separator(",")
+	spare := append(opts, sentinel)
+
+	var out Config
+	if err := yamlenv.Unmarshal(&out, opts...); err != nil {
+		t.Fatalf("Unmarshal(): unexpected error: %v", err)
+	}
+
+	if got, want := fmt.Sprintf("%p", spare[1]), fmt.Sprintf("%p", sentinel); got != want {
+		t.Errorf("Unmarshal(): got options modified, want them unchanged")
+	}
+}